@@ -0,0 +1,59 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Address is a parsed address of a scoreboard server
+type Address struct {
+	Network string // unix or tcp
+	Addr    string // socket path or host:port
+	TLS     bool   // connect with TLS, only for tcp
+}
+
+func (a Address) String() string {
+	switch {
+	case a.Network == "unix":
+		return "unix://" + a.Addr
+	case a.TLS:
+		return "tls://" + a.Addr
+	default:
+		return a.Addr
+	}
+}
+
+// ParseAddress parses the address of a scoreboard server. The address is one of
+//
+//	/run/scoreboard/sb.sock          a unix domain socket, if it contains a slash
+//	unix:///run/scoreboard/sb.sock   a unix domain socket
+//	host:port or tcp://host:port     a tcp socket
+//	tls://host:port                  a tcp socket secured with TLS
+func ParseAddress(s string) (Address, error) {
+	if s == "" {
+		return Address{}, fmt.Errorf("empty address")
+	}
+	scheme, rest := "", s
+	if i := strings.Index(s, "://"); i >= 0 {
+		scheme, rest = s[:i], s[i+3:]
+	} else if strings.HasPrefix(s, "unix:") {
+		scheme, rest = "unix", s[len("unix:"):]
+	} else if strings.ContainsRune(s, '/') {
+		scheme = "unix"
+	}
+	switch scheme {
+	case "unix":
+		if rest == "" {
+			return Address{}, fmt.Errorf("bad address %q: empty socket path", s)
+		}
+		return Address{Network: "unix", Addr: rest}, nil
+	case "", "tcp", "tls":
+		if _, _, err := net.SplitHostPort(rest); err != nil {
+			return Address{}, fmt.Errorf("bad address %q: %v", s, err)
+		}
+		return Address{Network: "tcp", Addr: rest, TLS: scheme == "tls"}, nil
+	default:
+		return Address{}, fmt.Errorf("bad address %q: unknown scheme %q", s, scheme)
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAddress(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want Address
+	}{
+		{"/run/scoreboard/sb.sock", Address{Network: "unix", Addr: "/run/scoreboard/sb.sock"}},
+		{"run/sb.sock", Address{Network: "unix", Addr: "run/sb.sock"}},
+		{"unix:///run/scoreboard/sb.sock", Address{Network: "unix", Addr: "/run/scoreboard/sb.sock"}},
+		{"unix:sb.sock", Address{Network: "unix", Addr: "sb.sock"}},
+		{"localhost:8000", Address{Network: "tcp", Addr: "localhost:8000"}},
+		{"tcp://[::1]:8000", Address{Network: "tcp", Addr: "[::1]:8000"}},
+		{"tls://sb.example.com:443", Address{Network: "tcp", Addr: "sb.example.com:443", TLS: true}},
+	} {
+		got, err := ParseAddress(tc.in)
+		if assert.NoError(t, err, tc.in) {
+			assert.Equal(t, tc.want, got, tc.in)
+		}
+	}
+}
+
+func TestParseAddressErrors(t *testing.T) {
+	for _, tc := range []struct {
+		in, err string
+	}{
+		{"", "empty address"},
+		{"localhost", `bad address "localhost": address localhost: missing port in address`},
+		{"unix://", `bad address "unix://": empty socket path`},
+		{"http://localhost:80", `bad address "http://localhost:80": unknown scheme "http"`},
+	} {
+		_, err := ParseAddress(tc.in)
+		assert.EqualError(t, err, tc.err, tc.in)
+	}
+}
+
+func TestAddressString(t *testing.T) {
+	for _, s := range []string{"unix:///run/sb.sock", "localhost:8000", "tls://sb.example.com:443"} {
+		addr, err := ParseAddress(s)
+		if assert.NoError(t, err) {
+			assert.Equal(t, s, addr.String())
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Package client talks to the scoreboard server
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NTHU-lsalab/sb"
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Options configure a Client
+type Options struct {
+	Address      string        // address of the server, see ParseAddress
+	TLSConfig    *tls.Config   // TLS configuration of tls:// addresses, the system roots if nil
+	DialTimeout  time.Duration // how long to wait for the connection, 10s if zero
+	CallTimeout  time.Duration // deadline of each attempt of a call, none if zero
+	Retries      int           // retries of a call failing with a transient error
+	RetryBackoff time.Duration // delay before the first retry, doubling for each further retry; 200ms if zero
+	Version      string        // build version of the program using the client, sent to the server
+}
+
+// Client is a connection to the scoreboard server
+type Client struct {
+	opts Options
+	conn *grpc.ClientConn
+	sb   pb.ScoreboardClient
+	adm  pb.AdminClient
+
+	mu             sync.Mutex
+	serverVersion  string
+	serverProtocol int
+}
+
+// Dial connects to the scoreboard server, waiting until the connection is up
+func Dial(ctx context.Context, opts Options) (*Client, error) {
+	addr, err := ParseAddress(opts.Address)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DialTimeout == 0 {
+		opts.DialTimeout = 10 * time.Second
+	}
+	if opts.RetryBackoff == 0 {
+		opts.RetryBackoff = 200 * time.Millisecond
+	}
+	c := &Client{opts: opts}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.FailOnNonTempDialError(true),
+		grpc.WithContextDialer(func(ctx context.Context, target string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, addr.Network, addr.Addr)
+		}),
+		grpc.WithUnaryInterceptor(c.intercept),
+		grpc.WithStreamInterceptor(c.interceptStream),
+	}
+	if addr.TLS {
+		config := opts.TLSConfig
+		if config == nil {
+			config = &tls.Config{}
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, opts.DialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(dialCtx, "passthrough:///"+addr.Addr, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", addr, err)
+	}
+	c.conn = conn
+	c.sb = pb.NewScoreboardClient(conn)
+	c.adm = pb.NewAdminClient(conn)
+	return c, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Scoreboard returns the generated client of the service, with the retries
+// and version negotiation of the Client
+func (c *Client) Scoreboard() pb.ScoreboardClient {
+	return c.sb
+}
+
+// Admin returns the generated client of the Admin service, with the retries
+// and version negotiation of the Client. Admin calls are not idempotent, so
+// Options.Retries should be zero.
+func (c *Client) Admin() pb.AdminClient {
+	return c.adm
+}
+
+// ServerVersion returns the build and protocol version of the server as of the
+// last call, or "" and 0 if the server did not tell
+func (c *Client) ServerVersion() (version string, protocol int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.serverVersion, c.serverProtocol
+}
+
+// Homework returns the definition of the homework
+func (c *Client) Homework(ctx context.Context, name string) (*pb.Homework, error) {
+	return c.sb.QueryHomework(ctx, &pb.QueryHomeworkRequest{Name: name})
+}
+
+// CaseTimes returns the times of the cases of the submission of the user stored
+// in the scoreboard
+func (c *Client) CaseTimes(ctx context.Context, homework, user string) (map[string]float64, error) {
+	times, err := c.sb.QueryCaseTimes(ctx, &pb.QueryCaseTimesRequest{Homework: homework, User: user})
+	if err != nil {
+		return nil, err
+	}
+	return times.Times, nil
+}
+
+// Submit submits the results of a user
+func (c *Client) Submit(ctx context.Context, submission *pb.UserSubmission) (*pb.SubmissionReply, error) {
+	return c.sb.Submit(ctx, submission)
+}
+
+// WatchBoard calls fn with each event of the board until ctx is done or fn
+// returns an error. When the stream breaks, it reconnects after the retry
+// backoff, resuming after the last event received, up to Retries times in a
+// row. Pass the resume token of the last event of an earlier watch to continue
+// it, or "" to start with a snapshot. Updates replace the rows of the same
+// users and remove the rows of RemovedUsers.
+func (c *Client) WatchBoard(ctx context.Context, homework, resumeToken string, fn func(*pb.BoardEvent) error) error {
+	backoff := c.opts.RetryBackoff
+	failures := 0
+	for {
+		stream, err := c.sb.WatchBoard(ctx, &pb.WatchBoardRequest{
+			Homework:    homework,
+			ResumeToken: resumeToken,
+		})
+		for err == nil {
+			var event *pb.BoardEvent
+			event, err = stream.Recv()
+			if err != nil {
+				break
+			}
+			failures = 0
+			backoff = c.opts.RetryBackoff
+			resumeToken = event.ResumeToken
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if status.Code(err) != codes.Unavailable || failures >= c.opts.Retries {
+			return err
+		}
+		failures++
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// retryable returns whether a call of the method failing with err may be retried.
+// A submission is only retried when it could not be sent; queries are also
+// retried when an attempt timed out.
+func retryable(method string, err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return true
+	case codes.DeadlineExceeded:
+		return method != "/pb.Scoreboard/Submit"
+	}
+	return false
+}
+
+// outgoing adds the versions of the handshake to the metadata of the call
+func (c *Client) outgoing(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx,
+		sb.ProtocolVersionKey, strconv.Itoa(sb.ProtocolVersion),
+		sb.ClientVersionKey, c.opts.Version)
+}
+
+func (c *Client) interceptStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(c.outgoing(ctx), desc, cc, method, opts...)
+}
+
+func (c *Client) intercept(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx = c.outgoing(ctx)
+	backoff := c.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if c.opts.CallTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, c.opts.CallTimeout)
+		}
+		var header metadata.MD
+		err := invoker(attemptCtx, method, req, reply, cc, append(opts, grpc.Header(&header))...)
+		cancel()
+		c.noteServerVersion(header)
+		if err == nil || attempt >= c.opts.Retries || !retryable(method, err) || ctx.Err() != nil {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) noteServerVersion(header metadata.MD) {
+	versions := header.Get(sb.ServerVersionKey)
+	protocols := header.Get(sb.ProtocolVersionKey)
+	if len(versions) == 0 && len(protocols) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(versions) > 0 {
+		c.serverVersion = versions[0]
+	}
+	if len(protocols) > 0 {
+		c.serverProtocol, _ = strconv.Atoi(protocols[0])
+	}
+}
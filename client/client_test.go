@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/NTHU-lsalab/sb"
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/NTHU-lsalab/sb/server"
+	"github.com/NTHU-lsalab/sb/server/servertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// flakyServer fails the first calls with the given status code
+type flakyServer struct {
+	pb.UnimplementedScoreboardServer
+	failures int
+	code     codes.Code
+	calls    int
+	md       metadata.MD
+}
+
+func (s *flakyServer) fail(ctx context.Context) error {
+	s.calls++
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs(sb.ServerVersionKey, "test", sb.ProtocolVersionKey, "7"))
+	if s.calls <= s.failures {
+		return status.Error(s.code, "flaky")
+	}
+	return nil
+}
+
+func (s *flakyServer) Submit(ctx context.Context, in *pb.UserSubmission) (*pb.SubmissionReply, error) {
+	if err := s.fail(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.SubmissionReply{Message: "created"}, nil
+}
+
+func (s *flakyServer) QueryHomework(ctx context.Context, in *pb.QueryHomeworkRequest) (*pb.Homework, error) {
+	if err := s.fail(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.Homework{Name: in.Name}, nil
+}
+
+func (s *flakyServer) QueryCaseTimes(ctx context.Context, in *pb.QueryCaseTimesRequest) (*pb.CaseTimes, error) {
+	if err := s.fail(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.CaseTimes{Times: map[string]float64{"01": 1.5}}, nil
+}
+
+// serve serves the server on a unix socket in a temporary directory
+func serve(t *testing.T, s pb.ScoreboardServer) (addr string, stop func()) {
+	dir, err := ioutil.TempDir("", "client-test")
+	require.NoError(t, err)
+	addr = filepath.Join(dir, "sb.sock")
+	stopServer := serveAt(t, addr, s)
+	return addr, func() {
+		stopServer()
+		os.RemoveAll(dir)
+	}
+}
+
+func serveAt(t *testing.T, addr string, s pb.ScoreboardServer) (stop func()) {
+	lis, err := net.Listen("unix", addr)
+	require.NoError(t, err)
+	gs := grpc.NewServer()
+	pb.RegisterScoreboardServer(gs, s)
+	go gs.Serve(lis)
+	return gs.Stop
+}
+
+func TestClient(t *testing.T) {
+	s := &flakyServer{}
+	addr, stop := serve(t, s)
+	defer stop()
+
+	c, err := Dial(context.Background(), Options{Address: addr, Version: "v1.2.3"})
+	require.NoError(t, err)
+	defer c.Close()
+
+	version, protocol := c.ServerVersion()
+	assert.Equal(t, "", version)
+	assert.Equal(t, 0, protocol)
+
+	hw, err := c.Homework(context.Background(), "hw1")
+	require.NoError(t, err)
+	assert.Equal(t, "hw1", hw.Name)
+	assert.Equal(t, []string{strconv.Itoa(sb.ProtocolVersion)}, s.md.Get(sb.ProtocolVersionKey))
+	assert.Equal(t, []string{"v1.2.3"}, s.md.Get(sb.ClientVersionKey))
+
+	version, protocol = c.ServerVersion()
+	assert.Equal(t, "test", version)
+	assert.Equal(t, 7, protocol)
+
+	times, err := c.CaseTimes(context.Background(), "hw1", "student")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"01": 1.5}, times)
+
+	reply, err := c.Submit(context.Background(), &pb.UserSubmission{Homework: "hw1"})
+	require.NoError(t, err)
+	assert.Equal(t, "created", reply.Message)
+}
+
+func TestClientRetries(t *testing.T) {
+	s := &flakyServer{failures: 2, code: codes.Unavailable}
+	addr, stop := serve(t, s)
+	defer stop()
+
+	c, err := Dial(context.Background(), Options{Address: "unix://" + addr, Retries: 2, RetryBackoff: time.Millisecond})
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.Homework(context.Background(), "hw1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, s.calls)
+
+	s.calls = 0
+	s.failures = 3
+	_, err = c.Homework(context.Background(), "hw1")
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 3, s.calls)
+}
+
+func TestClientDoesNotRetry(t *testing.T) {
+	s := &flakyServer{failures: 1, code: codes.DeadlineExceeded}
+	addr, stop := serve(t, s)
+	defer stop()
+
+	c, err := Dial(context.Background(), Options{Address: addr, Retries: 2, RetryBackoff: time.Millisecond})
+	require.NoError(t, err)
+	defer c.Close()
+
+	// a submission that may have reached the server is not sent twice
+	_, err = c.Submit(context.Background(), &pb.UserSubmission{Homework: "hw1"})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, 1, s.calls)
+
+	s.calls = 0
+	s.code = codes.NotFound
+	_, err = c.Homework(context.Background(), "hw1")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, 1, s.calls)
+}
+
+func TestDialFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "client-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = Dial(context.Background(), Options{Address: filepath.Join(dir, "missing.sock"), DialTimeout: time.Second})
+	assert.Error(t, err)
+}
+
+func TestWatchBoardReconnects(t *testing.T) {
+	s, err := server.New(server.Options{
+		Config:   server.StaticConfig{{Name: "hw", Cases: []string{"01"}}},
+		Storage:  servertest.NewMemoryStorage(),
+		Renderer: &servertest.RecordingRenderer{},
+		Logger:   log.New(ioutil.Discard, "", 0),
+	})
+	require.NoError(t, err)
+	dir, err := ioutil.TempDir("", "client-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	addr := filepath.Join(dir, "sb.sock")
+	stop := serveAt(t, addr, s)
+	defer func() { stop() }()
+
+	c, err := Dial(context.Background(), Options{Address: addr, Retries: 5, RetryBackoff: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer c.Close()
+
+	events := make(chan *pb.BoardEvent)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- c.WatchBoard(ctx, "hw", "", func(event *pb.BoardEvent) error {
+			events <- event
+			return nil
+		})
+	}()
+	assert.NotNil(t, (<-events).GetSnapshot())
+
+	// the update happens while the watcher is disconnected
+	stop()
+	stop = serveAt(t, addr, s)
+	_, err = c.Submit(context.Background(), &pb.UserSubmission{
+		User:     "ipc21s001",
+		Homework: "hw",
+		Results:  []*pb.Result{{Case: "01", Passed: true, Time: 1}},
+	})
+	require.NoError(t, err)
+
+	event := <-events
+	require.NotNil(t, event.GetUpdate(), "resumed with a snapshot")
+	assert.Equal(t, "ipc21s001", event.GetUpdate().Rows[0].User)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/NTHU-lsalab/sb/server"
+
+	"github.com/spf13/pflag"
+)
+
+// replayMain implements sb replay, which rebuilds the storage from the audit
+// log into a new directory, and returns the exit status
+func replayMain(args []string) int {
+	fs := pflag.NewFlagSet("sb replay", pflag.ExitOnError)
+	logFile := fs.String("audit-log", "audit.jsonl", "the audit log to replay")
+	to := fs.String("to", "storage.replay", "the directory to rebuild the storage in, which must not exist or be empty")
+	fs.Parse(args)
+
+	entries, err := ioutil.ReadDir(*to)
+	if err != nil && !os.IsNotExist(err) {
+		log.Print(err)
+		return 1
+	}
+	if len(entries) > 0 {
+		log.Printf("%s is not empty", *to)
+		return 1
+	}
+	f, err := os.Open(*logFile)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	defer f.Close()
+
+	logger := log.New(os.Stderr, "", 0)
+	s, err := server.New(server.Options{
+		Config:  server.ConfigDir("config"),
+		Storage: &server.DirStorage{Dir: *to, Logger: logger},
+		Logger:  logger,
+	})
+	if err != nil {
+		log.Printf("failed to load the scoreboard: %v", err)
+		return 1
+	}
+	summary, err := s.Replay(f)
+	if err != nil {
+		log.Printf("failed to replay %s: %v", *logFile, err)
+		return 1
+	}
+	fmt.Printf("Replayed %d submissions and %d admin actions of %d entries into %s, %d refused, %d skipped\n",
+		summary.Replayed, summary.Applied, summary.Entries, *to, summary.Refused, summary.Skipped)
+	return 0
+}
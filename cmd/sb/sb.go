@@ -1,374 +1,190 @@
 package main
 
 import (
-	"context"
-	"encoding/json"
-	"errors"
+	"crypto/ed25519"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"math"
 	"net"
+	"net/http"
 	"os"
+	"os/user"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/NTHU-lsalab/sb"
+	"github.com/NTHU-lsalab/sb/intrange"
 	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/NTHU-lsalab/sb/server"
 
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// Score is used to rank BoardEntries
-type Score struct {
-	NumPassed   int
-	TotalTime   float64
-	PenaltyTime float64
-}
-
-func (s Score) String() string {
-	return fmt.Sprintf("{%d %.2f}", s.NumPassed, s.TotalTime)
-}
-
-// Better returns whether the score s is better than o
-func (s Score) Better(o Score) bool {
-	if s.NumPassed == o.NumPassed {
-		return s.TotalTime < o.TotalTime
-	}
-	return s.NumPassed > o.NumPassed
-}
-
-func caseMapFromHomework(hw *pb.Homework) map[string]int {
-	caseMap := make(map[string]int)
-	for i, casename := range hw.Cases {
-		caseMap[casename] = i
-	}
-	return caseMap
-}
-
-func calcScore(hw *pb.Homework, results []*pb.Result) (s Score) {
-	caseMap := caseMapFromHomework(hw)
-	stats := make([]struct {
-		Passed bool
-		Time   float64
-	}, len(hw.Cases))
-	for _, result := range results {
-		i, ok := caseMap[result.Case]
-		if !ok {
-			continue
-		}
-		if result.Passed {
-			stats[i].Time = result.Time
-			stats[i].Passed = true
-		}
-	}
-	for _, stat := range stats {
-		if stat.Passed {
-			s.NumPassed++
-			s.TotalTime += stat.Time
-		} else {
-			s.PenaltyTime += hw.PenaltyTime
-		}
-	}
-	return
-}
-
-// BoardEntry = Score + Submission
-type BoardEntry struct {
-	Score
-	Submission *pb.StoredSubmission
-}
-
-// Board contains all the information for a homework
-type Board struct {
-	Homework       *pb.Homework
-	submissions    map[string]BoardEntry
-	submissionLock sync.Mutex
-}
-
-func isStudent(username string) bool {
-	return strings.HasPrefix(username, "ipc21s")
-}
-
-// Rows is for use in template
-func (b *Board) Rows() []TableRow {
-	rows := make([]TableRow, 0, len(b.submissions))
-	for _, boardEntry := range b.submissions {
-		rows = append(rows, TableRow{
-			BoardEntry: boardEntry,
-			Cells:      make([]TableCell, len(b.Homework.Cases)),
-		})
-	}
-	caseMap := caseMapFromHomework(b.Homework)
-	for rowi, row := range rows {
-		for _, result := range row.BoardEntry.Submission.Results {
-			if casei, ok := caseMap[result.Case]; ok {
-				rows[rowi].Cells[casei].result = result
-			}
-		}
-	}
-	sort.Slice(
-		rows,
-		func(i, j int) bool { return rows[i].Score.Better(rows[j].Score) },
-	)
-	rank := 0
-	for i := range rows {
-		if isStudent(rows[i].Submission.User) {
-			rank++
-			rows[i].rank = rank
-		} else {
-			rows[i].rank = -1
-		}
+// checkConfigs loads the homework configs and prints a summary of each,
+// returning false if any of them is broken
+func checkConfigs() bool {
+	glob, err := filepath.Glob("config/*.toml")
+	if err != nil {
+		panic(err) // malformed glob
 	}
-
-	for i := range b.Homework.Cases {
-		best := math.Inf(1)
-		for _, row := range rows {
-			if !isStudent(row.Submission.User) {
-				continue
-			}
-			r := row.Cells[i].result
-			if r != nil {
-				if r.Passed && r.Time < best {
-					best = r.Time
-				}
-			}
-		}
-		for _, row := range rows {
-			if !isStudent(row.Submission.User) {
-				continue
-			}
-			r := row.Cells[i].result
-			if r != nil {
-				if r.Passed && r.Time-0.1 < best {
-					row.Cells[i].best = true
+	ok := true
+	for _, filename := range glob {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					msg := fmt.Sprint(r)
+					if !strings.HasPrefix(msg, filename) {
+						msg = filename + ": " + msg
+					}
+					fmt.Println(msg)
+					ok = false
 				}
+			}()
+			hw := sb.LoadHomework(filename)
+			fmt.Printf("%s: target %s, runner %s\n", hw.Name, hw.Target, hw.Runner)
+			fmt.Printf("%s: %d cases: %s\n", hw.Name, len(hw.Cases), strings.Join(intrange.Compress(hw.Cases), " "))
+			if _, err := os.Stat(hw.Runner); err != nil {
+				fmt.Printf("%s: %v\n", hw.Name, err)
+				ok = false
 			}
-		}
+		}()
 	}
-	return rows
-}
-
-// TableRow is a helper object use in html template
-type TableRow struct {
-	BoardEntry
-	rank  int
-	Cells []TableCell
+	return ok
 }
 
-// Rank returns the rank of the row, or "-" if unapplicable
-func (tr TableRow) Rank() string {
-	if tr.rank < 0 {
-		return "—"
+// listen listens on the address. If it contains a slash, it is treated as a
+// unix domain socket, accessible to the group, otherwise as a tcp socket.
+func listen(address string) (net.Listener, error) {
+	if !strings.ContainsRune(address, '/') {
+		return net.Listen("tcp", address)
 	}
-	return strconv.Itoa(tr.rank)
-}
-
-// TableCell is a helper object in a html template which corresponds to a <td>
-type TableCell struct {
-	result *pb.Result
-	best   bool
-}
-
-// Class returns the class attribute of the <td>
-func (tc TableCell) Class() string {
-	if tc.best {
-		return "best"
-	}
-	r := tc.result
-	if r == nil {
-		return "empty"
-	}
-	if !r.Passed {
-		return "failed"
-	}
-	return ""
-}
-
-// Value returns the value enclosed within the <td> tag
-func (tc TableCell) Value() string {
-	r := tc.result
-	if r == nil {
-		return "—"
-	}
-	return fmt.Sprintf("%.2f", r.Time)
-}
-
-// Title returns the title attribute of the <td> tag
-func (tc TableCell) Title() string {
-	if tc.result == nil {
-		return "not submitted"
-	}
-	return tc.result.Verdict
-}
-
-func (b *Board) renderBoard() {
-	t0 := time.Now()
-	b.Rows()
-	dirname := filepath.Join(outputDir, b.Homework.Name)
-	err := os.MkdirAll(dirname, 0755)
-	if err != nil {
-		log.Printf("Failed to create directory: %s: %v", dirname, err)
-	}
-	filename := filepath.Join(dirname, "index.html")
-	w, err := os.Create(filename + "-")
-	if err != nil {
-		log.Printf("Failed to open %s: %v", filename, err)
-		return
+	err := os.Remove(address)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove existing unix socket: %v", err)
 	}
-	defer w.Close()
-	err = htmlTemplate.Execute(w, b)
+	lis, err := net.Listen("unix", address)
 	if err != nil {
-		log.Printf("Failed to render %s: %v", filename, err)
-		return
+		return nil, err
 	}
-	err = os.Rename(filename+"-", filename)
+	err = os.Chmod(address, 0660)
 	if err != nil {
-		log.Printf("Failed to move: %s: %v", filename, err)
+		lis.Close()
+		return nil, fmt.Errorf("failed to set unix socket permission: %v", err)
 	}
-	t1 := time.Now()
-	log.Printf("Rendered %s: %d submissions in %s",
-		b.Homework.Name, len(b.submissions), t1.Sub(t0))
+	return lis, nil
 }
 
-func (b *Board) updateSubmission(new *pb.UserSubmission) string {
-	b.submissionLock.Lock()
-	defer b.submissionLock.Unlock()
-	old, ok := b.submissions[new.User]
-	newScore := calcScore(b.Homework, new.Results)
-	if !ok || newScore.Better(old.Score) { // new <= old
-		b.submissions[new.User] = BoardEntry{
-			Score: newScore,
-			Submission: &pb.StoredSubmission{
-				User:    new.User,
-				Results: new.Results,
-			},
-		}
-
-		storeErr := storeSubmission(b.Homework.Name, b.submissions[new.User].Submission)
-		if storeErr != nil {
-			log.Printf("Failed to store submission %s/%s: %v", new.Homework, new.User, storeErr)
-		}
-
-		b.renderBoard()
-
-		if !ok {
-			return fmt.Sprintf("created %v", newScore)
-		}
-		return fmt.Sprintf("updated %v --> %v", old.Score, newScore)
-	}
-	return fmt.Sprintf("not updating %v -x-> %v", old.Score, newScore)
-}
-
-type server struct {
-	boards map[string]*Board
-}
-
-var _ pb.ScoreboardServer = &server{}
-
-func loadBoard(hw *pb.Homework) *Board {
-	b := &Board{
-		Homework:    hw,
-		submissions: make(map[string]BoardEntry),
-	}
-	hwDir := filepath.Join("storage", hw.Name)
-	err := os.MkdirAll(hwDir, 0755)
-	if err != nil {
-		log.Fatalf("Could not create directory for homework %s: %v", hw.Name, err)
-	}
-	glob, err := filepath.Glob(filepath.Join(hwDir, "*.json"))
-	if err != nil {
-		panic(err) // malformed glob
-	}
-	for _, filename := range glob {
-		be := BoardEntry{
-			Submission: &pb.StoredSubmission{},
+// lookupUIDs returns the uids of the users given by name or uid
+func lookupUIDs(users []string) ([]uint32, error) {
+	var uids []uint32
+	for _, name := range users {
+		u, err := user.Lookup(name)
+		if _, ok := err.(user.UnknownUserError); ok {
+			u, err = user.LookupId(name)
 		}
-		data, err := ioutil.ReadFile(filename)
 		if err != nil {
-			log.Printf("Failed to read stored submission %s: %v", filename, err)
+			return nil, err
 		}
-		err = json.Unmarshal(data, be.Submission)
+		uid, err := strconv.ParseUint(u.Uid, 10, 32)
 		if err != nil {
-			log.Printf("Failed to load stored submission %s: %v", filename, err)
+			return nil, fmt.Errorf("user %s has a non-numeric uid %s", name, u.Uid)
 		}
-		be.Score = calcScore(hw, be.Submission.Results)
-		b.submissions[be.Submission.User] = be
+		uids = append(uids, uint32(uid))
 	}
-	b.renderBoard()
-	return b
+	return uids, nil
 }
 
-func newServer() *server {
-	s := &server{
-		boards: make(map[string]*Board),
-	}
-	glob, err := filepath.Glob("config/*.toml")
+// verifyReceiptFile verifies the receipt saved by xjudge and prints the
+// submission, returning false if it is not signed with the key
+func verifyReceiptFile(key ed25519.PrivateKey, filename string) bool {
+	b, err := ioutil.ReadFile(filename)
 	if err != nil {
-		panic(err) // malformed glob
-	}
-	for _, filename := range glob {
-		hw := sb.LoadHomework(filename)
-		s.boards[hw.Name] = loadBoard(hw)
+		fmt.Println(err)
+		return false
 	}
-	return s
-}
-
-func (s *server) updateSubmission(new *pb.UserSubmission) (string, error) {
-	board, ok := s.boards[new.Homework]
-	if !ok {
-		return "", fmt.Errorf("No such homework: %q", new.Homework)
-	}
-	return board.updateSubmission(new), nil
-}
-
-func (s *server) handleSubmit(ctx context.Context, sub *pb.UserSubmission) (rep *pb.SubmissionReply, err error) {
-	msg, err := s.updateSubmission(sub)
+	reply := &pb.SubmissionReply{}
+	err = protojson.Unmarshal(b, reply)
 	if err != nil {
-		return
-	}
-	rep = &pb.SubmissionReply{Message: msg}
-	return
-}
-
-func (s *server) Submit(ctx context.Context, sub *pb.UserSubmission) (*pb.SubmissionReply, error) {
-	rep, err := s.handleSubmit(ctx, sub)
-	if err == nil {
-		log.Printf("Accepted %s/%s: %s", sub.Homework, sub.User, rep.Message)
-	} else {
-		log.Printf("Refused %s/%s: %v", sub.Homework, sub.User, err)
+		fmt.Printf("%s: %v\n", filename, err)
+		return false
 	}
-	return rep, err
-}
-
-func (s *server) QueryHomework(ctx context.Context, req *pb.QueryHomeworkRequest) (*pb.Homework, error) {
-	b, ok := s.boards[req.Name]
-	if !ok {
-		return nil, errors.New("No such homework")
+	record, err := server.VerifyReceipt(key.Public().(ed25519.PublicKey), reply.Receipt)
+	if err != nil {
+		fmt.Printf("%s: invalid receipt: %v\n", filename, err)
+		return false
 	}
-	return b.Homework, nil
+	fmt.Printf("%s: valid receipt of submission %s\n", filename, record.Id)
+	fmt.Println(protojson.Format(record))
+	return true
 }
 
 var serverAddress string
+var httpAddress string
 var outputDir string
+var checkConfig bool
+var minProtocolVersion int
+var maxSubmissionSize int
+var receiptKeyFile string
+var verifyReceipt string
+var auditLogFile string
+var admins []string
 
 func init() {
 	pflag.StringVar(&serverAddress, "address", sb.DefaultAddr,
 		"the address of the server to listen to. "+
 			"If it contains a slash, it is treated as a unix domain socket, "+
 			"otherwise it is treated as a tcp socket")
+	pflag.StringVar(&httpAddress, "http", "",
+		"the address to serve the read-only JSON API on, disabled if empty. "+
+			"If it contains a slash, it is treated as a unix domain socket")
 	pflag.StringVar(&outputDir, "outputdir", "out", "html output directory")
+	pflag.Uint64Var(&sb.MaxCases, "max-cases", sb.MaxCases, "the maximum number of cases of a homework")
+	pflag.IntVar(&minProtocolVersion, "min-protocol-version", 0, "refuse clients older than this protocol version, 0 accepts all clients")
+	pflag.IntVar(&maxSubmissionSize, "max-submission-size", server.DefaultMaxSubmissionSize, "the maximum encoded size of a submission in bytes")
+	pflag.StringVar(&receiptKeyFile, "receipt-key", "receipt.key", "the key to sign the receipts of submissions with, created if it does not exist unless verifying a receipt")
+	pflag.StringVar(&verifyReceipt, "verify-receipt", "", "verify the receipt file saved by xjudge, print the submission and exit")
+	pflag.StringVar(&auditLogFile, "audit-log", "audit.jsonl", "the append-only log of submissions, disabled if empty")
+	pflag.StringSliceVar(&admins, "admins", nil, "users, by name or uid, allowed to manage the boards with sbctl, besides root and the user running the server")
+	pflag.BoolVar(&checkConfig, "check-config", false, "check the homework configs, print a summary of each and exit")
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(replayMain(os.Args[2:]))
+	}
 	pflag.Parse()
 
-	err := os.MkdirAll(outputDir, 0755)
+	if checkConfig {
+		if !checkConfigs() {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if verifyReceipt != "" {
+		// verifying with a newly created key would only fail
+		receiptKey, err := server.LoadKey(receiptKeyFile)
+		if err != nil {
+			log.Fatalf("failed to load the receipt key: %v", err)
+		}
+		if !verifyReceiptFile(receiptKey, verifyReceipt) {
+			os.Exit(1)
+		}
+		return
+	}
+	receiptKey, err := server.LoadOrCreateKey(receiptKeyFile)
+	if err != nil {
+		log.Fatalf("failed to load the receipt key: %v", err)
+	}
+
+	adminUIDs, err := lookupUIDs(admins)
+	if err != nil {
+		log.Fatalf("failed to look up the admins: %v", err)
+	}
+	err = os.MkdirAll(outputDir, 0755)
 	if err != nil {
 		log.Fatalf("failed to create output directory %s: %v", outputDir, err)
 	}
@@ -376,27 +192,46 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to create storage directory %s: %v", sb.StorageDir, err)
 	}
-	network := "tcp"
-	if strings.ContainsRune(serverAddress, '/') {
-		network = "unix"
-		err = os.Remove(serverAddress)
-		if err != nil && !os.IsNotExist(err) {
-			log.Fatalf("failed to remove existing unix socket: %v", err)
-		}
-	}
-	lis, err := net.Listen(network, serverAddress)
+	lis, err := listen(serverAddress)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	if network == "unix" {
-		err = os.Chmod(serverAddress, 0660)
+	var auditLog *server.AuditLog
+	if auditLogFile != "" {
+		auditLog, err = server.OpenAuditLog(auditLogFile)
 		if err != nil {
-			log.Fatalf("failed to set unix socket permission")
+			log.Fatalf("failed to open the audit log: %v", err)
 		}
 	}
-	gs := grpc.NewServer()
-	s := newServer()
+	s, err := server.New(server.Options{
+		Config:   server.ConfigDir("config"),
+		Storage:  &server.DirStorage{Dir: sb.StorageDir},
+		Renderer: &server.HTMLRenderer{Dir: outputDir},
+
+		AuditLog:           auditLog,
+		ReceiptKey:         receiptKey,
+		MaxSubmissionSize:  maxSubmissionSize,
+		MinProtocolVersion: minProtocolVersion,
+		AdminUIDs:          adminUIDs,
+	})
+	if err != nil {
+		log.Fatalf("failed to load the scoreboard: %v", err)
+	}
+	if httpAddress != "" {
+		httpLis, err := listen(httpAddress)
+		if err != nil {
+			log.Fatalf("failed to listen for http: %v", err)
+		}
+		go func() {
+			log.Fatalf("failed to serve http: %v", http.Serve(httpLis, s.HTTPHandler()))
+		}()
+	}
+	gs := grpc.NewServer(
+		grpc.Creds(server.PeerCredentials()),
+		grpc.UnaryInterceptor(s.UnaryInterceptor()),
+		grpc.StreamInterceptor(s.StreamInterceptor()))
 	pb.RegisterScoreboardServer(gs, s)
+	pb.RegisterAdminServer(gs, s.Admin())
 	if err := gs.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
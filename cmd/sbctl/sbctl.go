@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NTHU-lsalab/sb"
+	"github.com/NTHU-lsalab/sb/client"
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"github.com/spf13/pflag"
+)
+
+const usage = `Usage: sbctl [flags] COMMAND [ARGS]
+
+Manages the boards of the scoreboard server. Only root, the user running the
+server and the users given to sb --admins may use it, on the unix socket.
+
+Commands:
+  boards                             list the boards
+  users HOMEWORK                     list the users of the board
+  show HOMEWORK USER                 show the entry of the user
+  delete HOMEWORK USER               delete the entry of the user
+  reset HOMEWORK USER                remove the results of the entry, keeping its notes
+  disqualify HOMEWORK USER [REASON]  stop ranking the user
+  requalify HOMEWORK USER [REASON]   lift the disqualification of the user
+  note HOMEWORK USER TEXT            add a note to the entry of the user
+  render [HOMEWORK]                  render the board, or all the boards
+  reload                             reload the homework configs
+
+Flags:
+`
+
+// command is a subcommand taking between minArgs and maxArgs arguments
+type command struct {
+	minArgs, maxArgs int
+	run              func(ctx context.Context, adm pb.AdminClient, args []string) error
+}
+
+// arg returns the i-th argument, or "" if there are fewer
+func arg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+func printReply(reply *pb.AdminReply, err error) error {
+	if err != nil {
+		return err
+	}
+	fmt.Println(reply.Message)
+	return nil
+}
+
+var commands = map[string]command{
+	"boards": {0, 0, func(ctx context.Context, adm pb.AdminClient, args []string) error {
+		list, err := adm.ListBoards(ctx, &pb.ListBoardsRequest{})
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "HOMEWORK\tCASES\tUSERS")
+		for _, b := range list.Boards {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", b.Homework, b.Cases, b.Users)
+		}
+		return w.Flush()
+	}},
+	"users": {1, 1, func(ctx context.Context, adm pb.AdminClient, args []string) error {
+		board, err := adm.ListUsers(ctx, &pb.ListUsersRequest{Homework: args[0]})
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "RANK\tUSER\tPASSED\tTOTAL TIME\tPENALTY TIME")
+		for _, row := range board.Rows {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%.2f\t%.2f\n", rank(row.Rank, row.Disqualified), row.User, row.NumPassed, row.TotalTime, row.PenaltyTime)
+		}
+		return w.Flush()
+	}},
+	"show": {2, 2, func(ctx context.Context, adm pb.AdminClient, args []string) error {
+		entry, err := adm.GetEntry(ctx, &pb.EntryRequest{Homework: args[0], User: args[1]})
+		if err != nil {
+			return err
+		}
+		printEntry(entry)
+		return nil
+	}},
+	"delete": {2, 2, func(ctx context.Context, adm pb.AdminClient, args []string) error {
+		return printReply(adm.DeleteEntry(ctx, &pb.EntryRequest{Homework: args[0], User: args[1]}))
+	}},
+	"reset": {2, 2, func(ctx context.Context, adm pb.AdminClient, args []string) error {
+		return printReply(adm.ResetEntry(ctx, &pb.EntryRequest{Homework: args[0], User: args[1]}))
+	}},
+	"disqualify": {2, 3, func(ctx context.Context, adm pb.AdminClient, args []string) error {
+		return printReply(adm.Disqualify(ctx, &pb.DisqualifyRequest{Homework: args[0], User: args[1], Reason: arg(args, 2)}))
+	}},
+	"requalify": {2, 3, func(ctx context.Context, adm pb.AdminClient, args []string) error {
+		return printReply(adm.Disqualify(ctx, &pb.DisqualifyRequest{Homework: args[0], User: args[1], Reason: arg(args, 2), Undo: true}))
+	}},
+	"note": {3, 3, func(ctx context.Context, adm pb.AdminClient, args []string) error {
+		return printReply(adm.AddNote(ctx, &pb.NoteRequest{Homework: args[0], User: args[1], Text: args[2]}))
+	}},
+	"render": {0, 1, func(ctx context.Context, adm pb.AdminClient, args []string) error {
+		return printReply(adm.Render(ctx, &pb.RenderRequest{Homework: arg(args, 0)}))
+	}},
+	"reload": {0, 0, func(ctx context.Context, adm pb.AdminClient, args []string) error {
+		return printReply(adm.ReloadConfig(ctx, &pb.ReloadConfigRequest{}))
+	}},
+}
+
+// rank returns the rank as shown on the board
+func rank(rank int32, disqualified bool) string {
+	switch {
+	case disqualified:
+		return "DQ"
+	case rank == 0:
+		return "—"
+	}
+	return fmt.Sprint(rank)
+}
+
+func formatTime(unixNano int64) string {
+	if unixNano == 0 {
+		return "—"
+	}
+	return time.Unix(0, unixNano).Format("2006-01-02 15:04:05")
+}
+
+func printEntry(entry *pb.Entry) {
+	sub := entry.Submission
+	fmt.Printf("user:       %s\n", sub.User)
+	fmt.Printf("rank:       %s\n", rank(entry.Rank, sub.Disqualified))
+	fmt.Printf("score:      %d passed, total time %.2f, penalty time %.2f\n",
+		entry.Score.NumPassed, entry.Score.TotalTime, entry.Score.PenaltyTime)
+	if sub.Id != "" {
+		fmt.Printf("submission: %s at %s\n", sub.Id, formatTime(sub.TimeUnixNano))
+	}
+	if len(sub.Results) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "CASE\tPASSED\tTIME\tVERDICT")
+		for _, r := range sub.Results {
+			fmt.Fprintf(w, "%s\t%t\t%.2f\t%s\n", r.Case, r.Passed, r.Time, r.Verdict)
+		}
+		w.Flush()
+	}
+	if len(sub.Notes) > 0 {
+		fmt.Println()
+		for _, note := range sub.Notes {
+			fmt.Printf("%s %s: %s\n", formatTime(note.TimeUnixNano), note.Peer, note.Text)
+		}
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+	fs := pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
+	fs.SortFlags = false
+	server := fs.String("server", sb.DefaultAddr, "Address of the scoreboard server. The admin service is only available on the unix socket.")
+	timeout := fs.Duration("timeout", 30*time.Second, "Give up the command after this long.")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[1:])
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	args := fs.Args()[1:]
+	if !ok {
+		log.Printf("unknown command %q", fs.Arg(0))
+		fs.Usage()
+		os.Exit(2)
+	}
+	if len(args) < cmd.minArgs || len(args) > cmd.maxArgs {
+		log.Printf("wrong number of arguments for %s", fs.Arg(0))
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	c, err := client.Dial(ctx, client.Options{Address: *server, Version: sb.Version})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+	if err := cmd.run(ctx, c.Admin(), args); err != nil {
+		log.Fatal(err)
+	}
+}
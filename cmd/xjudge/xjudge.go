@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
 	"os/user"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/NTHU-lsalab/sb"
 	"github.com/NTHU-lsalab/sb/judge"
@@ -26,6 +29,8 @@ func parseOptions() *judge.Options {
 	fs := pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
 	fs.SortFlags = false
 
+	fs.BoolVar(&opt.NoSubmit, "no-submit", false, "Judge locally without submitting the results to the scoreboard. If the scoreboard is unreachable, the cached homework definition is used.")
+	fs.BoolVar(&opt.Offline, "offline", false, "Use the cached homework definition without connecting to the scoreboard. Implies --no-submit.")
 	fs.StringVarP(&opt.Chdir, "chdir", "C", "", "Change the directory before judging")
 	fs.StringVar(&opt.AsUser, "as", currentUser.Username, "Run the judge as the user. Privileged option.")
 	fs.StringVar(&opt.RuleFile, "rule", "", "Run the judge with the given rule file. Privileged option.")
@@ -36,20 +41,59 @@ func parseOptions() *judge.Options {
 	}
 	fs.StringVar(&opt.Homework, "homework", homework, "Judge the specific homework.")
 	fs.StringVar(&opt.Bin, "bin", "", "Skip compiling and use the given binary. Privileged option.")
-	fs.StringVar(&opt.Server, "server", sb.DefaultAddr, "Address of the scoreboard server. If it contains a slash, it is treated as a unix domain socket, otherwise it is treated as a tcp socket.")
-	fs.IntVar(&opt.MedianOf, "median-of", 1, "Run each case multiple times and pick the median. Must be an odd integer.")
+	fs.StringVar(&opt.Server, "server", sb.DefaultAddr, "Address of the scoreboard server: a unix domain socket path or unix:///path, host:port for tcp, or tls://host:port for tcp with TLS.")
+	fs.IntVar(&opt.MedianOf, "median-of", 1, "Run each case multiple times. The case passes if most runs pass, with the median of the passed runs. Must be an odd integer.")
+	fs.BoolVar(&opt.ConfigOrder, "config-order", false, "Run the cases in the order of the homework config. By default, the cases that took the longest in previous runs are run first.")
+	fs.BoolVar(&opt.FailFast, "fail-fast", false, "Stop judging after the first failing case.")
+	fs.IntVar(&opt.Retries, "retries", 0, "Retry runs ending with an internal error, such as a runner that failed to start, up to this many times.")
+	fs.DurationVar(&opt.RetryBackoff, "retry-backoff", time.Second, "Delay before the first retry of a run with an internal error. The delay doubles for each further retry.")
+	fs.BoolVar(&opt.Timing, "timing", false, "Run each case repeatedly until the timing is stable. The submitted time follows the timing policy of the homework.")
+	fs.IntVar(&opt.Warmup, "warmup", 1, "Number of unmeasured warmup runs of each case with --timing.")
+	fs.IntVar(&opt.MinRuns, "min-runs", 3, "Minimum number of measured runs of each case with --timing.")
+	fs.IntVar(&opt.MaxRuns, "max-runs", 10, "Maximum number of measured runs of each case with --timing.")
+	fs.Float64Var(&opt.TargetCI, "target-ci", 0.05, "With --timing, stop repeating a case when the half-width of the 95% confidence interval of its mean time is below this fraction of the mean.")
 
 	fs.StringArrayVarP(&opt.ExcludeCases, "exclude", "x", nil, "Exclude the given test cases. Specify this option multiple times to exclude multiple test cases.")
-	fs.StringArrayVarP(&opt.IncludeCases, "include", "i", nil, "Include the given test cases. Specify this option multiple times to include multiple test cases. --include takes higher priority than exclude. If --include is specified but --exclude is not specified, the judge will only run only the --include'd test cases. For both --include and --exclude, []-expansion is supported. --include=case[01-03] expands to --include=case01 --include=case02 --include=case03. --exclude=case[01,04] expands to --exclude=case01 --exclude=case04.")
+	fs.StringArrayVarP(&opt.IncludeCases, "include", "i", nil, "Include the given test cases. Specify this option multiple times to include multiple test cases. --include takes higher priority than exclude. If --include is specified but --exclude is not specified, the judge will only run only the --include'd test cases. For both --include and --exclude, []-expansion and shell-style * and ? globs are supported. --include=case[01-03] expands to --include=case01 --include=case02 --include=case03. --exclude=case[01,04] expands to --exclude=case01 --exclude=case04. --exclude='large*' excludes all cases starting with large.")
+	fs.StringVar(&opt.CasesFrom, "cases-from", "", "Include the test cases listed in the given file, one pattern per line as in --include. Empty lines and lines starting with # are ignored.")
+
+	fs.Int64Var(&opt.BuildCacheSize, "build-cache-size", 1024, "Maximum size in MiB of the cache of executables built from unchanged sources. 0 disables the cache.")
+	fs.BoolVar(&opt.Resume, "resume", false, "Resume the interrupted run of the same source files, judging only the cases not judged yet.")
+	fs.BoolVar(&opt.SubmitPartial, "submit-partial", false, "Submit the results even if the run was interrupted before judging all cases.")
+	fs.StringVar(&opt.Format, "format", judge.FormatText, "Format of the report: text, json, junit or tap. The text format is logged to stderr while judging, other formats are written to stdout or the --output file.")
+	fs.StringVarP(&opt.Output, "output", "o", "", "Write the report to the given file instead of stdout.")
 
 	fs.BoolVar(&opt.Debug, "debug", false, "Output debug messages")
+	fs.BoolVar(&opt.Cgroup, "cgroup", false, "Account the CPU time and memory of runners with cgroup v2. The judge must be started in a delegated cgroup, e.g. with systemd-run --user --scope -p Delegate=yes.")
 
-	fs.Parse(os.Args[1:])
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "test" {
+		// xjudge test: judge locally without submitting
+		opt.NoSubmit = true
+		args = args[1:]
+	}
+	fs.Parse(args)
 
 	return opt
 }
 
 func main() {
+	judge.RunSandboxHelper()
+	log.SetFlags(0)
 	options := parseOptions()
-	judge.MainOptions(options)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupted := make(chan os.Signal, 1)
+	go func() {
+		<-interrupted
+		log.Println("Cleaning up...")
+		cancel()
+	}()
+	signal.Notify(interrupted, os.Interrupt)
+
+	_, err := judge.Run(ctx, judge.Config{Options: *options})
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -1,16 +1,42 @@
 package colors
 
+import (
+	"os"
+)
+
+// Enabled controls whether the functions in this package output colors.
+// It is true by default if stderr is a terminal and NO_COLOR is not set.
+var Enabled = enabledByDefault()
+
+func enabledByDefault() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	fi, err := os.Stderr.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+func wrap(code, s string) string {
+	if !Enabled {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
 // Red wraps the input string in ANSI red
 func Red(s string) string {
-	return "\x1b[31m" + string(s) + "\x1b[0m"
+	return wrap("31", s)
 }
 
 // Green wraps the input string in ANSI green
 func Green(s string) string {
-	return "\x1b[32m" + string(s) + "\x1b[0m"
+	return wrap("32", s)
 }
 
 // Yellow wraps the input string in ANSI yellow
 func Yellow(s string) string {
-	return "\x1b[33m" + string(s) + "\x1b[0m"
+	return wrap("33", s)
 }
@@ -7,5 +7,27 @@ const DefaultAddr = "/run/scoreboard/sb.sock"
 // users who have read access to it
 const PrivilegeFile = "/etc/judge.priv"
 
+// SessionKeyFile is the key signing the sessions of interrupted judge runs. It is
+// only readable with the setgid privilege of the judge, so that users cannot
+// forge the results of a session.
+const SessionKeyFile = "/etc/xjudge/session.key"
+
 // StorageDir is the directory the scoreboard server stores submissions
 const StorageDir = "storage"
+
+// ProtocolVersion is the version of the scoreboard protocol. It is increased
+// whenever a change to scoreboard.proto changes the meaning of a request.
+const ProtocolVersion = 1
+
+// Version is the build version of the programs, set at link time with
+// -ldflags "-X github.com/NTHU-lsalab/sb.Version=..."
+var Version = "dev"
+
+// Metadata keys of the version handshake. Clients send their protocol and
+// build version with every call, and the server replies with its own in the
+// response header.
+const (
+	ProtocolVersionKey = "sb-protocol-version"
+	ClientVersionKey   = "sb-client-version"
+	ServerVersionKey   = "sb-server-version"
+)
@@ -1,6 +1,7 @@
 package sb
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/NTHU-lsalab/sb/intrange"
@@ -9,6 +10,32 @@ import (
 	"github.com/BurntSushi/toml"
 )
 
+// MaxCases is the maximum number of cases a homework may have, checked before
+// expanding the case patterns so that a typo cannot exhaust the memory
+var MaxCases uint64 = 100000
+
+// expandCases expands the case patterns, panicking if they have more than MaxCases cases
+func expandCases(filename string, patterns []string) []string {
+	parsed := make([]*intrange.Pattern, len(patterns))
+	total := uint64(0)
+	for i, pattern := range patterns {
+		p, err := intrange.Parse(pattern)
+		if err != nil {
+			panic(fmt.Errorf("%s: %v", filename, err))
+		}
+		parsed[i] = p
+		total += p.Count()
+		if p.Count() > MaxCases || total > MaxCases {
+			panic(fmt.Errorf("%s: more than the maximum of %d cases, at %q", filename, MaxCases, pattern))
+		}
+	}
+	var cases []string
+	for _, p := range parsed {
+		cases = append(cases, p.Values()...)
+	}
+	return cases
+}
+
 func LoadHomework(filename string) *pb.Homework {
 	hw := new(struct {
 		Target      string
@@ -16,6 +43,18 @@ func LoadHomework(filename string) *pb.Homework {
 		Files       []*pb.SourceFile
 		PenaltyTime toml.Primitive `toml:"penalty_time"`
 		Cases       []string
+		Metrics     []*pb.MetricColumn `toml:"metric_columns"`
+		Policy      string             `toml:"timing_policy"`
+		Reference   map[string]float64 `toml:"reference_times"`
+		Limits      *struct {
+			AddressSpace int64 `toml:"address_space"`
+			CPUTime      int64 `toml:"cpu_time"`
+			Processes    int64 `toml:"processes"`
+			OpenFiles    int64 `toml:"open_files"`
+			FileSize     int64 `toml:"file_size"`
+			Memory       int64 `toml:"memory"`
+			Sandbox      bool  `toml:"sandbox"`
+		}
 	})
 	metadata, err := toml.DecodeFile(filename, hw)
 	if err != nil {
@@ -36,17 +75,60 @@ func LoadHomework(filename string) *pb.Homework {
 		}
 		penaltyTime = float64(penaltyTimeAsInt)
 	}
-	var expandedCases []string
-	for _, casestr := range hw.Cases {
-		expandedCases = append(expandedCases, intrange.MustExpand(casestr)...)
+	hw.Cases = expandCases(filename, hw.Cases)
+	for _, col := range hw.Metrics {
+		if col.Title == "" {
+			col.Title = col.Metric
+		}
+		if col.Format == "" {
+			col.Format = "%.2f"
+		}
+		switch col.Aggregate {
+		case "":
+			col.Aggregate = "mean"
+		case "sum", "mean", "min", "max":
+		default:
+			panic(fmt.Errorf("%s: unknown aggregate %q for metric %q", filename, col.Aggregate, col.Metric))
+		}
+	}
+	switch hw.Policy {
+	case "":
+		hw.Policy = "median"
+	case "median", "mean", "min":
+	default:
+		panic(fmt.Errorf("%s: unknown timing policy %q", filename, hw.Policy))
+	}
+	var referenceTimes map[string]float64
+	for pattern, t := range hw.Reference {
+		if referenceTimes == nil {
+			referenceTimes = make(map[string]float64)
+		}
+		for _, casename := range expandCases(filename, []string{pattern}) {
+			referenceTimes[casename] = t
+		}
+	}
+	var limits *pb.Limits
+	if hw.Limits != nil {
+		limits = &pb.Limits{
+			AddressSpace: hw.Limits.AddressSpace,
+			CpuTime:      hw.Limits.CPUTime,
+			Processes:    hw.Limits.Processes,
+			OpenFiles:    hw.Limits.OpenFiles,
+			FileSize:     hw.Limits.FileSize,
+			Memory:       hw.Limits.Memory,
+			Sandbox:      hw.Limits.Sandbox,
+		}
 	}
-	hw.Cases = expandedCases
 	return &pb.Homework{
-		Name:        name,
-		Target:      hw.Target,
-		Runner:      hw.Runner,
-		Files:       hw.Files,
-		PenaltyTime: penaltyTime,
-		Cases:       hw.Cases,
+		Name:           name,
+		Target:         hw.Target,
+		Runner:         hw.Runner,
+		Files:          hw.Files,
+		PenaltyTime:    penaltyTime,
+		Cases:          hw.Cases,
+		MetricColumns:  hw.Metrics,
+		Limits:         limits,
+		TimingPolicy:   hw.Policy,
+		ReferenceTimes: referenceTimes,
 	}
 }
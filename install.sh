@@ -5,6 +5,7 @@ sudo install -dm750 -oscoreboardd -gscoreboardd /run/scoreboard
 
 # Judge
 sudo install -Dm2711 -gscoreboardd xjudge /usr/local/bin/xjudge
+[ -f /etc/xjudge/session.key ] || head -c32 /dev/urandom | sudo install -Dm440 -gscoreboardd /dev/stdin /etc/xjudge/session.key
 for hw in hw1 lab2 hw2
 do
 	sudo ln -sf /usr/local/bin/xjudge /usr/local/bin/$hw-judge
@@ -0,0 +1,204 @@
+package intrange
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Compress folds the names back into patterns, the inverse of Expand.
+// Consecutive names differing in a number are folded into a range, keeping the
+// zero-padding, and ranges of ranges are folded again. Expanding the patterns in
+// order gives back the names.
+func Compress(names []string) []string {
+	patterns := make([]string, len(names))
+	for i, name := range names {
+		patterns[i] = escape(name)
+	}
+	for {
+		folded := compressOnce(patterns)
+		if len(folded) == len(patterns) {
+			return folded
+		}
+		patterns = folded
+	}
+}
+
+// escape escapes the characters of the name which are special to Expand
+func escape(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case '\\', '[', '{':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(name[i])
+	}
+	return b.String()
+}
+
+// splitLastNumber splits the pattern around its last number outside of ranges
+func splitLastNumber(pattern string) (prefix, number, suffix string, ok bool) {
+	start, end := -1, -1
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++
+		case c == '[':
+			for i < len(pattern) && pattern[i] != ']' {
+				if pattern[i] == '\\' {
+					i++
+				}
+				i++
+			}
+		case isDigit(c):
+			start = i
+			for i+1 < len(pattern) && isDigit(pattern[i+1]) {
+				i++
+			}
+			end = i + 1
+		}
+	}
+	if start == -1 {
+		return "", "", "", false
+	}
+	return pattern[:start], pattern[start:end], pattern[end:], true
+}
+
+// compressOnce folds consecutive patterns differing only in their last number
+func compressOnce(patterns []string) []string {
+	var result []string
+	for i := 0; i < len(patterns); {
+		prefix, number, suffix, ok := splitLastNumber(patterns[i])
+		if !ok {
+			result = append(result, patterns[i])
+			i++
+			continue
+		}
+		numbers := []string{number}
+		j := i + 1
+		for ; j < len(patterns); j++ {
+			p, n, s, ok := splitLastNumber(patterns[j])
+			if !ok || p != prefix || s != suffix {
+				break
+			}
+			numbers = append(numbers, n)
+		}
+		if len(numbers) == 1 {
+			result = append(result, patterns[i])
+		} else {
+			result = append(result, prefix+"["+strings.Join(compressNumbers(numbers), ",")+"]"+suffix)
+		}
+		i = j
+	}
+	return result
+}
+
+// numberRun is a run of numbers with a constant difference or ratio
+type numberRun struct {
+	strs     []string
+	vals     []int64
+	add, mul bool
+}
+
+// extend adds the number to the run if the run can still be written as a range
+func (r *numberRun) extend(s string, v int64) bool {
+	n := len(r.vals)
+	add, mul := true, true
+	if n >= 1 {
+		first := r.vals[0]
+		last := r.vals[n-1]
+		if v == last {
+			return false
+		}
+		if n >= 2 {
+			add = r.add && v-last == r.vals[1]-first
+			mul = r.mul && (v > last) == (r.vals[1] > first) &&
+				ratioStep(last, v) > 0 && ratioStep(last, v) == ratioStep(first, r.vals[1])
+		} else {
+			mul = ratioStep(last, v) > 0
+		}
+		width := padWidth(r.strs[0], s)
+		for i, val := range r.vals {
+			if formatInt(val, width) != r.strs[i] {
+				return false
+			}
+		}
+		if formatInt(v, width) != s {
+			return false
+		}
+	}
+	if !add && !mul {
+		return false
+	}
+	r.strs = append(r.strs, s)
+	r.vals = append(r.vals, v)
+	r.add, r.mul = add, mul
+	return true
+}
+
+// ratioStep returns the ratio from a to b written as a multiplying range step, or 0
+func ratioStep(a, b int64) int64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	if a > b {
+		a, b = b, a
+	}
+	if b%a != 0 || b/a < 2 {
+		return 0
+	}
+	return b / a
+}
+
+func (r *numberRun) String() string {
+	n := len(r.strs)
+	diff := int64(0)
+	if n >= 2 {
+		diff = r.vals[1] - r.vals[0]
+		if diff < 0 {
+			diff = -diff
+		}
+	}
+	switch {
+	case n == 2 && diff == 1:
+		return r.strs[0] + "-" + r.strs[1]
+	case n < 3:
+		return strings.Join(r.strs, ",")
+	case r.add:
+		if diff == 1 {
+			return r.strs[0] + "-" + r.strs[n-1]
+		}
+		return r.strs[0] + "-" + r.strs[n-1] + ":" + strconv.FormatInt(diff, 10)
+	default:
+		return r.strs[0] + "-" + r.strs[n-1] + ":x" + strconv.FormatInt(ratioStep(r.vals[0], r.vals[1]), 10)
+	}
+}
+
+// compressNumbers folds the numbers into range items
+func compressNumbers(numbers []string) []string {
+	vals := make([]int64, len(numbers))
+	for i, number := range numbers {
+		v, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			// too long to be a range bound
+			return numbers
+		}
+		vals[i] = v
+	}
+	var items []string
+	for i := 0; i < len(numbers); {
+		r := &numberRun{}
+		j := i
+		for j < len(numbers) && r.extend(numbers[j], vals[j]) {
+			j++
+		}
+		if j-i == 2 && j < len(numbers) {
+			// the second number may start a longer run
+			j = i + 1
+			r = &numberRun{strs: numbers[i:j], vals: vals[i:j]}
+		}
+		items = append(items, r.String())
+		i = j
+	}
+	return items
+}
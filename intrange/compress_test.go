@@ -0,0 +1,52 @@
+package intrange
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func expandAll(patterns []string) []string {
+	var names []string
+	for _, pattern := range patterns {
+		names = append(names, MustExpand(pattern)...)
+	}
+	return names
+}
+
+func TestCompress(t *testing.T) {
+	for _, tc := range []struct {
+		names    []string
+		patterns []string
+	}{
+		{MustExpand("case[01-40]"), []string{"case[01-40]"}},
+		{MustExpand("[7-11]"), []string{"[7-11]"}},
+		{[]string{"1", "3", "4", "5"}, []string{"[1,3-5]"}},
+		{[]string{"1", "2"}, []string{"[1-2]"}},
+		{[]string{"1", "3"}, []string{"[1,3]"}},
+		{MustExpand("n[1-64:x2]"), []string{"n[1-64:x2]"}},
+		{MustExpand("n[64-1:x4]"), []string{"n[64-1:x4]"}},
+		{MustExpand("[10-01:3]"), []string{"[10-01:3]"}},
+		{MustExpand("n[1-2]_t[01-03]"), []string{"n[1-2]_t[01-03]"}},
+		{[]string{"small", "large", "x1"}, []string{"small", "large", "x1"}},
+		{[]string{`a[1]`, `a[2]`, `b{c\`}, []string{`a\[[1-2]]`, `b\{c\\`}},
+		{nil, nil},
+	} {
+		assert.Equal(t, tc.patterns, Compress(tc.names), "%v", tc.names)
+	}
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	for _, names := range [][]string{
+		{"9", "10", "08", "007"},
+		{"2", "4", "2", "4"},
+		{"1", "2", "4", "8", "9", "10"},
+		{"case1", "case1", "case2"},
+		{"a1b2", "a1b3", "a2b2", "a2b3", "a3b2"},
+		{"x99999999999999999999", "x99999999999999999998"},
+		MustExpand("{small,large}[1-3]_[a-c]"),
+	} {
+		assert.Equal(t, names, expandAll(Compress(names)), fmt.Sprint(names))
+	}
+}
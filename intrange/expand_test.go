@@ -41,3 +41,38 @@ func TestExpandMultipeRanges(t *testing.T) {
 		"2hello5world11",
 	})
 }
+
+func TestExpandSteps(t *testing.T) {
+	assert.Equal(t, []string{"n1", "n4", "n16", "n64"}, MustExpand("n[1-64:x4]"))
+}
+
+func TestExpandAlternatives(t *testing.T) {
+	assert.Equal(t, []string{"small1", "small2", "large"}, MustExpand("{small[1-2],large}"))
+	assert.Equal(t, []string{"a-x", "a-y", "b-x", "b-y"}, MustExpand("{a,b}-{x,y}"))
+	assert.Equal(t, []string{"ab", "acd", "ace"}, MustExpand("a{b,c{d,e}}"))
+	assert.Equal(t, []string{"a", "b"}, MustExpand("{a,b}"))
+	assert.Equal(t, []string{"x,y}"}, MustExpand("x,y}"))
+}
+
+func TestExpandEscapes(t *testing.T) {
+	assert.Equal(t, []string{"[1-2]"}, MustExpand(`\[1-2]`))
+	assert.Equal(t, []string{"{a,b}"}, MustExpand(`\{a,b}`))
+	assert.Equal(t, []string{"a]1", "a]2"}, MustExpand(`a][1-2]`))
+	assert.Equal(t, []string{`a\b`}, MustExpand(`a\\b`))
+}
+
+func TestExpandErrors(t *testing.T) {
+	for pattern, pos := range map[string]int{
+		"case[1-":    4,
+		"case{a,b":   4,
+		"case[1-a]":  5,
+		"x[1-9:y]":   6,
+		`trailing\`:  8,
+		"a[1]{b,[c}": 7,
+	} {
+		_, err := Expand(pattern)
+		if assert.IsType(t, &SyntaxError{}, err, pattern) {
+			assert.Equal(t, pos, err.(*SyntaxError).Pos, pattern)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package intrange
+
+import (
+	"math"
+)
+
+// node is a part of a parsed pattern. The values of a node can be counted and
+// indexed without expanding them.
+type node interface {
+	count() uint64
+	at(i uint64) string
+}
+
+func addSaturated(a, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+	return a + b
+}
+
+func mulSaturated(a, b uint64) uint64 {
+	if a != 0 && b > math.MaxUint64/a {
+		return math.MaxUint64
+	}
+	return a * b
+}
+
+// literal is plain text
+type literal string
+
+func (l literal) count() uint64 {
+	return 1
+}
+
+func (l literal) at(i uint64) string {
+	return string(l)
+}
+
+// choice holds the items of a [] range or the alternatives of a {} group
+type choice []node
+
+func (c choice) count() uint64 {
+	n := uint64(0)
+	for _, item := range c {
+		n = addSaturated(n, item.count())
+	}
+	return n
+}
+
+func (c choice) at(i uint64) string {
+	for _, item := range c {
+		n := item.count()
+		if i < n {
+			return item.at(i)
+		}
+		i -= n
+	}
+	return ""
+}
+
+// sequence is a concatenation of nodes, the last one varying fastest
+type sequence []node
+
+func (s sequence) count() uint64 {
+	n := uint64(1)
+	for _, part := range s {
+		n = mulSaturated(n, part.count())
+	}
+	return n
+}
+
+func (s sequence) at(i uint64) string {
+	parts := make([]string, len(s))
+	for j := len(s) - 1; j >= 0; j-- {
+		n := s[j].count()
+		parts[j] = s[j].at(i % n)
+		i /= n
+	}
+	var length int
+	for _, part := range parts {
+		length += len(part)
+	}
+	b := make([]byte, 0, length)
+	for _, part := range parts {
+		b = append(b, part...)
+	}
+	return string(b)
+}
+
+// intRange is a range of integers
+type intRange struct {
+	start      int64
+	n          uint64
+	step       int64
+	multiply   bool
+	descending bool
+	width      int
+}
+
+func (r intRange) count() uint64 {
+	return r.n
+}
+
+func (r intRange) at(i uint64) string {
+	v := r.start
+	switch {
+	case r.multiply && r.descending:
+		for ; i > 0; i-- {
+			v /= r.step
+		}
+	case r.multiply:
+		for ; i > 0; i-- {
+			v *= r.step
+		}
+	case r.descending:
+		v = int64(uint64(v) - i*uint64(r.step))
+	default:
+		v = int64(uint64(v) + i*uint64(r.step))
+	}
+	return formatInt(v, r.width)
+}
+
+// letters is a range of letters
+type letters struct {
+	start      byte
+	n          uint64
+	step       int
+	descending bool
+}
+
+func (r letters) count() uint64 {
+	return r.n
+}
+
+func (r letters) at(i uint64) string {
+	if r.descending {
+		return string(rune(int(r.start) - int(i)*r.step))
+	}
+	return string(rune(int(r.start) + int(i)*r.step))
+}
+
+// Pattern is a parsed pattern, whose values can be counted and iterated
+// without expanding all of them in memory
+type Pattern struct {
+	root node
+}
+
+// Parse parses a pattern of the syntax of Expand
+func Parse(s string) (*Pattern, error) {
+	p := &parser{s: s}
+	seq, err := p.parseSequence(false)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{root: seq}, nil
+}
+
+// ParseRange parses a range of the syntax of Range
+func ParseRange(s string) (*Pattern, error) {
+	p := &parser{s: s}
+	values, err := p.parseItems(false)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{root: values}, nil
+}
+
+// Count returns the number of values of the pattern, saturating at math.MaxUint64
+func (p *Pattern) Count() uint64 {
+	return p.root.count()
+}
+
+// Iter returns an iterator over the values of the pattern
+func (p *Pattern) Iter() *Iterator {
+	return &Iterator{root: p.root, n: p.root.count()}
+}
+
+// Values expands all the values of the pattern
+func (p *Pattern) Values() []string {
+	var values []string
+	for it := p.Iter(); it.Next(); {
+		values = append(values, it.Value())
+	}
+	return values
+}
+
+// Iterator iterates over the values of a pattern in order.
+//
+//	for it := pattern.Iter(); it.Next(); {
+//		fmt.Println(it.Value())
+//	}
+type Iterator struct {
+	root  node
+	n     uint64
+	next  uint64
+	value string
+}
+
+// Next advances the iterator to the next value, returning false when there is none
+func (it *Iterator) Next() bool {
+	if it.next >= it.n {
+		return false
+	}
+	it.value = it.root.at(it.next)
+	it.next++
+	return true
+}
+
+// Value returns the current value
+func (it *Iterator) Value() string {
+	return it.value
+}
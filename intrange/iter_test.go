@@ -0,0 +1,50 @@
+package intrange
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCount(t *testing.T) {
+	for pattern, count := range map[string]uint64{
+		"hello":                      1,
+		"case[0-99999999]":           100000000,
+		"[1-64:x2]":                  7,
+		"[64-1:x2]":                  7,
+		"[10-1:3]":                   4,
+		"[a-z]{small,large[1-3]}":    26 * 4,
+		"[0-999999][0-999999][0-99]": 100000000000000,
+		"[-9223372036854775808-9223372036854775807]":                                           math.MaxUint64,
+		"[-9223372036854775808-9223372036854775807][-9223372036854775808-9223372036854775807]": math.MaxUint64,
+	} {
+		p, err := Parse(pattern)
+		require.NoError(t, err, pattern)
+		assert.Equal(t, count, p.Count(), pattern)
+	}
+}
+
+func TestIterator(t *testing.T) {
+	for _, pattern := range []string{"case[01-03]{a,b[1,5-7:2]}", "n[1-64:x2]", "[f-a:2]x[3-1]"} {
+		p, err := Parse(pattern)
+		require.NoError(t, err)
+		var values []string
+		for it := p.Iter(); it.Next(); {
+			values = append(values, it.Value())
+		}
+		assert.Equal(t, MustExpand(pattern), values)
+		assert.EqualValues(t, len(values), p.Count())
+	}
+}
+
+func TestIteratorLazy(t *testing.T) {
+	p, err := ParseRange("0-999999999999")
+	require.NoError(t, err)
+	it := p.Iter()
+	require.True(t, it.Next())
+	assert.Equal(t, "0", it.Value())
+	require.True(t, it.Next())
+	assert.Equal(t, "1", it.Value())
+}
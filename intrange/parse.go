@@ -0,0 +1,313 @@
+package intrange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError describes a malformed pattern
+type SyntaxError struct {
+	Pattern string
+	Pos     int // byte offset of the error in Pattern
+	Msg     string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d of %q", e.Msg, e.Pos+1, e.Pattern)
+}
+
+type parser struct {
+	s       string
+	pos     int
+	bracket int // position of the `[` of the range being parsed
+}
+
+func (p *parser) unclosedBracket() error {
+	return p.errorf(p.bracket, "opening `[` without enclosing `]`")
+}
+
+func (p *parser) errorf(pos int, format string, args ...interface{}) error {
+	return &SyntaxError{Pattern: p.s, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.s)
+}
+
+// parseSequence parses text, ranges and alternatives up to the end of the pattern,
+// or up to the next unescaped `,` or `}` inside alternatives
+func (p *parser) parseSequence(inBraces bool) (sequence, error) {
+	var seq sequence
+	var lit strings.Builder
+	flush := func() {
+		if lit.Len() > 0 {
+			seq = append(seq, literal(lit.String()))
+			lit.Reset()
+		}
+	}
+	for !p.done() {
+		c := p.s[p.pos]
+		switch {
+		case c == '\\':
+			if p.pos+1 == len(p.s) {
+				return nil, p.errorf(p.pos, "trailing backslash")
+			}
+			lit.WriteByte(p.s[p.pos+1])
+			p.pos += 2
+		case c == '[':
+			flush()
+			p.bracket = p.pos
+			p.pos++
+			values, err := p.parseItems(true)
+			if err != nil {
+				return nil, err
+			}
+			if p.done() {
+				return nil, p.unclosedBracket()
+			}
+			p.pos++
+			seq = append(seq, values)
+		case c == '{':
+			flush()
+			open := p.pos
+			p.pos++
+			alts, err := p.parseAlternatives(open)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, alts)
+		case inBraces && (c == ',' || c == '}'):
+			flush()
+			return seq, nil
+		default:
+			lit.WriteByte(c)
+			p.pos++
+		}
+	}
+	flush()
+	return seq, nil
+}
+
+// parseAlternatives parses the alternatives after the `{` at open, and the closing `}`
+func (p *parser) parseAlternatives(open int) (choice, error) {
+	var alts choice
+	for {
+		seq, err := p.parseSequence(true)
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, seq)
+		if p.done() {
+			return nil, p.errorf(open, "opening `{` without enclosing `}`")
+		}
+		c := p.s[p.pos]
+		p.pos++
+		if c == '}' {
+			return alts, nil
+		}
+	}
+}
+
+// parseItems parses comma separated range items up to the end of the pattern,
+// or up to the closing `]` if bracketed, which is not consumed
+func (p *parser) parseItems(bracketed bool) (choice, error) {
+	var values choice
+	for {
+		item, err := p.parseItem(bracketed)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, item)
+		if p.done() || (bracketed && p.s[p.pos] == ']') {
+			return values, nil
+		}
+		p.pos++ // ','
+	}
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// readValue reads a bound of a range item. A `-` starts a negative number
+// only at the beginning of the value.
+func (p *parser) readValue(bracketed bool) (value string, escaped bool, err error) {
+	var b strings.Builder
+	for !p.done() {
+		c := p.s[p.pos]
+		if c == '\\' {
+			if p.pos+1 == len(p.s) {
+				return "", false, p.errorf(p.pos, "trailing backslash")
+			}
+			b.WriteByte(p.s[p.pos+1])
+			p.pos += 2
+			escaped = true
+			continue
+		}
+		if c == ',' || c == ':' || (bracketed && c == ']') {
+			break
+		}
+		if c == '-' && (b.Len() > 0 || escaped || p.pos+1 == len(p.s) || !isDigit(p.s[p.pos+1])) {
+			break
+		}
+		b.WriteByte(c)
+		p.pos++
+	}
+	return b.String(), escaped, nil
+}
+
+func (p *parser) atItemEnd(bracketed bool) bool {
+	return p.done() || p.s[p.pos] == ',' || (bracketed && p.s[p.pos] == ']')
+}
+
+// parseItem parses a single value, or a range with an optional step
+func (p *parser) parseItem(bracketed bool) (node, error) {
+	start := p.pos
+	lo, loEscaped, err := p.readValue(bracketed)
+	if err != nil {
+		return nil, err
+	}
+	if p.atItemEnd(bracketed) {
+		return literal(lo), nil
+	}
+	if p.s[p.pos] == ':' {
+		return nil, p.errorf(p.pos, "step without a range")
+	}
+	p.pos++ // '-'
+	hi, hiEscaped, err := p.readValue(bracketed)
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() && p.s[p.pos] == '-' {
+		return nil, p.errorf(p.pos, "unexpected `-`")
+	}
+	step := int64(1)
+	multiply := false
+	if !p.done() && p.s[p.pos] == ':' {
+		p.pos++
+		stepPos := p.pos
+		for !p.atItemEnd(bracketed) {
+			p.pos++
+		}
+		stepstr := p.s[stepPos:p.pos]
+		if strings.HasPrefix(stepstr, "x") {
+			multiply = true
+			stepstr = stepstr[1:]
+		}
+		step, err = strconv.ParseInt(stepstr, 10, 64)
+		if err != nil || step < 1 || (multiply && step < 2) {
+			return nil, p.errorf(stepPos, "bad step %q", p.s[stepPos:p.pos])
+		}
+	}
+	if bracketed && p.done() {
+		return nil, p.unclosedBracket()
+	}
+	item := p.s[start:p.pos]
+	switch {
+	case !loEscaped && !hiEscaped && isInteger(lo) && isInteger(hi):
+		r, err := integerRange(lo, hi, step, multiply)
+		if err != nil {
+			return nil, p.errorf(start, "bad range %q: %v", item, err)
+		}
+		return r, nil
+	case isLetter(lo) && isLetter(hi) && isUpper(lo[0]) == isUpper(hi[0]):
+		if multiply {
+			return nil, p.errorf(start, "bad range %q: letter ranges cannot multiply", item)
+		}
+		return letterRange(lo[0], hi[0], step), nil
+	default:
+		return nil, p.errorf(start, "bad range %q", item)
+	}
+}
+
+func isInteger(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isUpper(c byte) bool {
+	return 'A' <= c && c <= 'Z'
+}
+
+func isLetter(s string) bool {
+	return len(s) == 1 && (isUpper(s[0]) || 'a' <= s[0] && s[0] <= 'z')
+}
+
+// padWidth returns the width to zero-pad the integers of a range to,
+// which is the width of the bounds written with leading zeros
+func padWidth(bounds ...string) int {
+	width := 0
+	for _, b := range bounds {
+		digits := strings.TrimPrefix(b, "-")
+		if len(digits) > 1 && digits[0] == '0' && len(digits) > width {
+			width = len(digits)
+		}
+	}
+	return width
+}
+
+func formatInt(v int64, width int) string {
+	if v < 0 {
+		return fmt.Sprintf("-%0*d", width, uint64(-(v+1))+1)
+	}
+	return fmt.Sprintf("%0*d", width, v)
+}
+
+// integerRange returns the integers from lo to hi, adding or multiplying by step
+// towards hi
+func integerRange(lo, hi string, step int64, multiply bool) (node, error) {
+	a, err := strconv.ParseInt(lo, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	b, err := strconv.ParseInt(hi, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if multiply && (a <= 0 || b <= 0) {
+		return nil, fmt.Errorf("bounds of a multiplying range must be positive")
+	}
+	r := intRange{
+		start:      a,
+		step:       step,
+		multiply:   multiply,
+		descending: a > b,
+		width:      padWidth(lo, hi),
+		n:          1,
+	}
+	switch {
+	case multiply && a <= b:
+		for v := a; v <= b/step; v *= step {
+			r.n++
+		}
+	case multiply:
+		for v := a; v/step >= b; v /= step {
+			r.n++
+		}
+	case a <= b:
+		r.n = addSaturated((uint64(b)-uint64(a))/uint64(step), 1)
+	default:
+		r.n = addSaturated((uint64(a)-uint64(b))/uint64(step), 1)
+	}
+	return r, nil
+}
+
+// letterRange returns the letters from lo to hi
+func letterRange(lo, hi byte, step int64) node {
+	r := letters{start: lo, step: int(step), descending: lo > hi}
+	if lo <= hi {
+		r.n = uint64(int(hi-lo)/r.step + 1)
+	} else {
+		r.n = uint64(int(lo-hi)/r.step + 1)
+	}
+	return r
+}
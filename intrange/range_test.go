@@ -26,3 +26,42 @@ func TestMultipleRanges(t *testing.T) {
 	assert.Equal(t, MustRange("1-3,7-11"), []string{"1", "2", "3", "7", "8", "9", "10", "11"})
 	assert.Equal(t, MustRange("01-3,7-11"), []string{"01", "02", "03", "7", "8", "9", "10", "11"})
 }
+
+func TestStepRange(t *testing.T) {
+	assert.Equal(t, []string{"1", "3", "5", "7"}, MustRange("1-8:2"))
+	assert.Equal(t, []string{"1", "2", "4", "8", "16", "32", "64"}, MustRange("1-64:x2"))
+	assert.Equal(t, []string{"001", "004", "016", "064"}, MustRange("001-100:x4"))
+}
+
+func TestDescendingRange(t *testing.T) {
+	assert.Equal(t, []string{"10", "9", "8"}, MustRange("10-8"))
+	assert.Equal(t, []string{"10", "07", "04", "01"}, MustRange("10-01:3"))
+	assert.Equal(t, []string{"64", "16", "4", "1"}, MustRange("64-1:x4"))
+}
+
+func TestNegativeRange(t *testing.T) {
+	assert.Equal(t, []string{"-2", "-1", "0", "1"}, MustRange("-2-1"))
+	assert.Equal(t, []string{"-1", "-2", "-3"}, MustRange("-1--3"))
+	assert.Equal(t, []string{"-5"}, MustRange("-5"))
+}
+
+func TestLetterRange(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, MustRange("a-c"))
+	assert.Equal(t, []string{"F", "D", "B"}, MustRange("F-A:2"))
+	assert.Equal(t, []string{"small", "large"}, MustRange("small,large"))
+}
+
+func TestEscapedRange(t *testing.T) {
+	assert.Equal(t, []string{"a-c", "d,e"}, MustRange(`a\-c,d\,e`))
+}
+
+func TestBadRange(t *testing.T) {
+	for _, s := range []string{"1-a", "a-B", "1-2-3", "1:2", "1-9:0", "1-9:x1", "0-9:x2", "a-c:x2", "foo-bar", `1\`} {
+		_, err := Range(s)
+		assert.Error(t, err, s)
+	}
+	_, err := Range("1,3-x")
+	if assert.IsType(t, &SyntaxError{}, err) {
+		assert.Equal(t, 2, err.(*SyntaxError).Pos)
+	}
+}
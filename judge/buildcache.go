@@ -0,0 +1,192 @@
+package judge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// buildCache is a per-user cache of executables keyed by the hash of the sources
+// they are built from. Entries are evicted in least recently used order when the
+// cache grows over maxSize bytes.
+//
+// The cache is writable by the user, so each entry holds a signature of its key
+// and of the hash of the executable, checked on every lookup.
+type buildCache struct {
+	dir        string
+	maxSize    int64
+	signingKey []byte
+}
+
+// signatureFile is the name of the signature in a cache entry
+const signatureFile = ".signature"
+
+func newBuildCache(maxSize int64, signingKey []byte) (*buildCache, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	c := &buildCache{
+		dir:        filepath.Join(dir, "builds"),
+		maxSize:    maxSize,
+		signingKey: signingKey,
+	}
+	return c, asUser(func() error {
+		return os.MkdirAll(c.dir, 0755)
+	})
+}
+
+// signature returns the signature of the executable cached under the key
+func (c *buildCache) signature(key, exe string) (string, error) {
+	h := sha256.New()
+	err := hashFile(h, key, exe)
+	if err != nil {
+		return "", err
+	}
+	return sign(c.signingKey, h.Sum(nil)), nil
+}
+
+// buildKey returns the cache key of the sources copied to dir and the build command
+func buildKey(rule Rule, dir string) (string, error) {
+	h := sha256.New()
+	for _, filename := range rule.Mandantory {
+		err := hashFile(h, filename, filepath.Join(dir, filename))
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, pair := range rule.Optional {
+		err := hashFile(h, pair.Name, filepath.Join(dir, pair.Name))
+		if err != nil {
+			return "", err
+		}
+	}
+	fmt.Fprintf(h, "%s\x00", strings.Join(buildArgs("", rule.Target), "\x00"))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// lookup returns the cached executable of the key
+func (c *buildCache) lookup(key, target string) (string, bool) {
+	entry := filepath.Join(c.dir, key)
+	exe := filepath.Join(entry, filepath.Base(target))
+	err := asUser(func() error {
+		signature, err := ioutil.ReadFile(filepath.Join(entry, signatureFile))
+		if err != nil {
+			return err
+		}
+		actual, err := c.signature(key, exe)
+		if err != nil {
+			return err
+		}
+		if !hmac.Equal(signature, []byte(actual)) {
+			return errors.New("bad signature")
+		}
+		now := time.Now()
+		os.Chtimes(entry, now, now)
+		return nil
+	})
+	if err != nil {
+		return "", false
+	}
+	return exe, true
+}
+
+// store copies the executable into the cache and evicts old entries
+func (c *buildCache) store(key, exe, target string) error {
+	return asUser(func() error {
+		tmp, err := ioutil.TempDir(c.dir, ".tmp.*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		src, err := os.Open(exe)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		dst, err := os.OpenFile(filepath.Join(tmp, filepath.Base(target)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0755)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dst, src)
+		if err != nil {
+			dst.Close()
+			return err
+		}
+		err = dst.Close()
+		if err != nil {
+			return err
+		}
+		signature, err := c.signature(key, dst.Name())
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(tmp, signatureFile), []byte(signature), 0644)
+		if err != nil {
+			return err
+		}
+		err = os.Chmod(tmp, 0755)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(tmp, filepath.Join(c.dir, key))
+		if err != nil && !os.IsExist(err) {
+			return err
+		}
+		return c.evict()
+	})
+}
+
+type buildCacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// evict removes the least recently used entries until the cache fits in maxSize
+func (c *buildCache) evict() error {
+	infos, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	var entries []buildCacheEntry
+	var total int64
+	for _, info := range infos {
+		if !info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		entry := buildCacheEntry{path: filepath.Join(c.dir, info.Name()), modTime: info.ModTime()}
+		files, err := ioutil.ReadDir(entry.path)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			entry.size += f.Size()
+		}
+		total += entry.size
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+	for _, entry := range entries {
+		if total <= c.maxSize {
+			break
+		}
+		err = os.RemoveAll(entry.path)
+		if err != nil {
+			return err
+		}
+		total -= entry.size
+	}
+	return nil
+}
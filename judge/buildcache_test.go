@@ -0,0 +1,77 @@
+package judge
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildcache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	exe := filepath.Join(dir, "exe")
+	require.NoError(t, ioutil.WriteFile(exe, make([]byte, 100), 0755))
+
+	// each entry holds 100 bytes of executable and 64 bytes of signature
+	c := &buildCache{dir: filepath.Join(dir, "cache"), maxSize: 400}
+	require.NoError(t, os.Mkdir(c.dir, 0755))
+
+	require.NoError(t, c.store("a", exe, "hw"))
+	require.NoError(t, c.store("b", exe, "hw"))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(c.dir, "a"), old, old))
+	require.NoError(t, os.Chtimes(filepath.Join(c.dir, "b"), old.Add(time.Minute), old.Add(time.Minute)))
+
+	cached, ok := c.lookup("a", "hw")
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(c.dir, "a", "hw"), cached)
+
+	require.NoError(t, c.store("c", exe, "hw"))
+	_, ok = c.lookup("a", "hw")
+	assert.True(t, ok)
+	_, ok = c.lookup("b", "hw")
+	assert.False(t, ok)
+	_, ok = c.lookup("c", "hw")
+	assert.True(t, ok)
+}
+
+func TestBuildCacheVerifiesSignature(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildcache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	exe := filepath.Join(dir, "exe")
+	require.NoError(t, ioutil.WriteFile(exe, []byte("built"), 0755))
+
+	c := &buildCache{dir: filepath.Join(dir, "cache"), maxSize: 1024, signingKey: []byte("secret")}
+	require.NoError(t, os.Mkdir(c.dir, 0755))
+	require.NoError(t, c.store("a", exe, "hw"))
+	require.NoError(t, c.store("b", exe, "hw"))
+
+	cached, ok := c.lookup("a", "hw")
+	require.True(t, ok)
+	require.NoError(t, ioutil.WriteFile(cached, []byte("forged"), 0755))
+	_, ok = c.lookup("a", "hw")
+	assert.False(t, ok)
+
+	// the signature of another entry does not match
+	signature, err := ioutil.ReadFile(filepath.Join(c.dir, "b", signatureFile))
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(c.dir, "c"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(c.dir, "c", "hw"), []byte("built"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(c.dir, "c", signatureFile), signature, 0644))
+	_, ok = c.lookup("c", "hw")
+	assert.False(t, ok)
+
+	// entries signed with another key are not used
+	other := &buildCache{dir: c.dir, maxSize: 1024, signingKey: []byte("other")}
+	_, ok = other.lookup("b", "hw")
+	assert.False(t, ok)
+	_, ok = c.lookup("b", "hw")
+	assert.True(t, ok)
+}
@@ -0,0 +1,79 @@
+package judge
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// cacheDir returns the directory for the judge's per-user cache
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "xjudge"), nil
+}
+
+func cachedHomeworkFile(name string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "homework", filepath.Base(name)+".json"), nil
+}
+
+// saveCachedHomework stores the homework definition for use in offline mode
+func saveCachedHomework(hw *pb.Homework) error {
+	return asUser(func() error {
+		filename, err := cachedHomeworkFile(hw.Name)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(filepath.Dir(filename), 0755)
+		if err != nil {
+			return err
+		}
+		b, err := protojson.Marshal(hw)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filename+"-", b, 0644)
+		if err != nil {
+			return err
+		}
+		return os.Rename(filename+"-", filename)
+	})
+}
+
+// loadCachedHomework loads the homework definition saved by saveCachedHomework
+func loadCachedHomework(name string) (hw *pb.Homework, err error) {
+	err = asUser(func() error {
+		filename, err := cachedHomeworkFile(name)
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadFile(filename)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("homework %s has not been cached, run the judge online once first", name)
+		}
+		if err != nil {
+			return err
+		}
+		hw = &pb.Homework{}
+		err = protojson.Unmarshal(b, hw)
+		if err != nil {
+			return fmt.Errorf("corrupt cache %s: %v", filename, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hw, nil
+}
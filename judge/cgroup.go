@@ -0,0 +1,172 @@
+package judge
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const cgroup2Mount = "/sys/fs/cgroup"
+
+// cgroupRoot is a delegated cgroup v2 directory under which the judge creates
+// a child cgroup for each runner to account its resource usage.
+//
+// The judge has to be started in a cgroup writable by the user, for example with
+// `systemd-run --user --scope -p Delegate=yes xjudge ...`.
+type cgroupRoot struct {
+	dir    string
+	memory bool // whether the memory controller is enabled for the children
+}
+
+// ownCgroup returns the cgroup v2 path of the current process
+func ownCgroup() (string, error) {
+	data, err := ioutil.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	return parseCgroup(data)
+}
+
+// parseCgroup returns the cgroup v2 path in the contents of /proc/<pid>/cgroup
+func parseCgroup(data []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "0::") {
+			return line[3:], nil
+		}
+	}
+	return "", errors.New("not running in a cgroup v2 hierarchy")
+}
+
+// setupCgroup moves the judge itself into a leaf cgroup so that controllers can be
+// enabled for the runners' cgroups
+func setupCgroup() (*cgroupRoot, error) {
+	own, err := ownCgroup()
+	if err != nil {
+		return nil, err
+	}
+	root := &cgroupRoot{dir: filepath.Join(cgroup2Mount, own)}
+	if filepath.Base(root.dir) == "judge" {
+		// already moved by a previous setupCgroup
+		root.dir = filepath.Dir(root.dir)
+	}
+	self := filepath.Join(root.dir, "judge")
+	err = os.Mkdir(self, 0755)
+	if err != nil && !os.IsExist(err) {
+		return nil, fmt.Errorf("cgroup %s is not delegated: %v", root.dir, err)
+	}
+	err = writeCgroupFile(self, "cgroup.procs", strconv.Itoa(os.Getpid()))
+	if err != nil {
+		return nil, err
+	}
+	root.memory = writeCgroupFile(root.dir, "cgroup.subtree_control", "+memory") == nil
+	return root, nil
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	return ioutil.WriteFile(filepath.Join(dir, name), []byte(value), 0644)
+}
+
+// runnerCgroup is the cgroup of a single runner process tree
+type runnerCgroup struct {
+	dir    string
+	memory bool
+}
+
+// newRunnerCgroup creates a cgroup for the runner with the given name
+func (r *cgroupRoot) newRunnerCgroup(name string) (*runnerCgroup, error) {
+	dir := filepath.Join(r.dir, name)
+	err := os.Mkdir(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &runnerCgroup{dir: dir, memory: r.memory}, nil
+}
+
+// open opens the cgroup directory, to start a process in the cgroup with
+// SysProcAttr.CgroupFD
+func (c *runnerCgroup) open() (*os.File, error) {
+	return os.Open(c.dir)
+}
+
+// setMemoryMax limits the memory usage of the cgroup
+func (c *runnerCgroup) setMemoryMax(bytes int64) error {
+	if !c.memory {
+		return errors.New("memory controller not enabled")
+	}
+	return writeCgroupFile(c.dir, "memory.max", strconv.FormatInt(bytes, 10))
+}
+
+// oomKilled returns whether any process in the cgroup was killed by the OOM killer
+func (c *runnerCgroup) oomKilled() bool {
+	if !c.memory {
+		return false
+	}
+	data, err := ioutil.ReadFile(filepath.Join(c.dir, "memory.events"))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			return fields[1] != "0"
+		}
+	}
+	return false
+}
+
+// usage reads the accumulated resource usage of the cgroup
+func (c *runnerCgroup) usage() (usage resourceUsage, err error) {
+	data, err := ioutil.ReadFile(filepath.Join(c.dir, "cpu.stat"))
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		usec, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "user_usec":
+			usage.UserTime = float64(usec) / 1e6
+		case "system_usec":
+			usage.SysTime = float64(usec) / 1e6
+		}
+	}
+	if c.memory {
+		// memory.peak is only available since Linux 5.19, leave MaxRSS unset otherwise
+		data, err := ioutil.ReadFile(filepath.Join(c.dir, "memory.peak"))
+		if err == nil {
+			peak, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+			if err == nil {
+				usage.MaxRSS = peak / 1024
+			}
+		}
+	}
+	return usage, nil
+}
+
+// remove kills the remaining processes in the cgroup and removes it
+func (c *runnerCgroup) remove() (err error) {
+	writeCgroupFile(c.dir, "cgroup.kill", "1")
+	for i := 0; i < 10; i++ {
+		err = os.Remove(c.dir)
+		if err == nil || os.IsNotExist(err) {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return err
+}
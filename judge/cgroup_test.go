@@ -0,0 +1,67 @@
+package judge
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCgroup(t *testing.T) {
+	path, err := parseCgroup([]byte("1:name=systemd:/user.slice\n0::/user.slice/user-1000.slice/session-3.scope\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "/user.slice/user-1000.slice/session-3.scope", path)
+	_, err = parseCgroup([]byte("1:cpu:/\n"))
+	assert.Error(t, err)
+}
+
+// fakeCgroup returns a runner cgroup in a temporary directory, standing in for
+// the cgroup filesystem
+func fakeCgroup(t *testing.T, memory bool) *runnerCgroup {
+	dir, err := ioutil.TempDir("", "cgroup-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	c, err := (&cgroupRoot{dir: dir, memory: memory}).newRunnerCgroup("case-0-0")
+	require.NoError(t, err)
+	return c
+}
+
+func TestCgroupUsage(t *testing.T) {
+	c := fakeCgroup(t, true)
+	writeFile(t, filepath.Join(c.dir, "cpu.stat"), "usage_usec 3500000\nuser_usec 2500000\nsystem_usec 1000000\n", 0644)
+	writeFile(t, filepath.Join(c.dir, "memory.peak"), "10485760\n", 0644)
+	usage, err := c.usage()
+	require.NoError(t, err)
+	assert.Equal(t, resourceUsage{UserTime: 2.5, SysTime: 1, MaxRSS: 10240}, usage)
+
+	// without memory.peak, MaxRSS is left to the rusage of the runner
+	require.NoError(t, os.Remove(filepath.Join(c.dir, "memory.peak")))
+	usage, err = c.usage()
+	require.NoError(t, err)
+	assert.Zero(t, usage.MaxRSS)
+
+	_, err = fakeCgroup(t, true).usage()
+	assert.Error(t, err)
+}
+
+func TestCgroupMemory(t *testing.T) {
+	c := fakeCgroup(t, true)
+	require.NoError(t, c.setMemoryMax(64<<20))
+	b, err := ioutil.ReadFile(filepath.Join(c.dir, "memory.max"))
+	require.NoError(t, err)
+	assert.Equal(t, "67108864", string(b))
+
+	assert.False(t, c.oomKilled())
+	writeFile(t, filepath.Join(c.dir, "memory.events"), "low 0\nhigh 0\nmax 3\noom 1\noom_kill 0\n", 0644)
+	assert.False(t, c.oomKilled())
+	writeFile(t, filepath.Join(c.dir, "memory.events"), "low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n", 0644)
+	assert.True(t, c.oomKilled())
+
+	c = fakeCgroup(t, false)
+	assert.Error(t, c.setMemoryMax(64<<20))
+	writeFile(t, filepath.Join(c.dir, "memory.events"), "oom_kill 1\n", 0644)
+	assert.False(t, c.oomKilled())
+}
@@ -0,0 +1,132 @@
+package judge
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/NTHU-lsalab/sb"
+	"github.com/NTHU-lsalab/sb/intrange"
+)
+
+// expandPatterns expands the []-ranges of the case patterns
+func expandPatterns(patterns []string) ([]string, error) {
+	var expanded []string
+	for _, pattern := range patterns {
+		p, err := intrange.Parse(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("bad case pattern %q: %v", pattern, err)
+		}
+		if p.Count() > sb.MaxCases {
+			return nil, fmt.Errorf("case pattern %q expands to more than %d cases", pattern, sb.MaxCases)
+		}
+		expanded = append(expanded, p.Values()...)
+	}
+	return expanded, nil
+}
+
+// matchCases returns whether each case matches any of the patterns.
+// Patterns are expanded with intrange, then matched as shell globs.
+// It is an error if a pattern matches no case.
+func matchCases(cases, patterns []string) ([]bool, error) {
+	globs, err := expandPatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]bool, len(cases))
+	for _, glob := range globs {
+		found := false
+		for i, kase := range cases {
+			ok, err := path.Match(glob, kase)
+			if err != nil {
+				return nil, fmt.Errorf("bad case pattern %q: %v", glob, err)
+			}
+			if ok {
+				matched[i] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%q matches no case, valid cases are: %s", glob, strings.Join(cases, " "))
+		}
+	}
+	return matched, nil
+}
+
+// filterCases selects the cases to judge. If there are only include patterns,
+// only the included cases are kept, otherwise the excluded cases are dropped
+// unless they are also included.
+func filterCases(cases, include, exclude []string) (kept, dropped []string, err error) {
+	included, err := matchCases(cases, include)
+	if err != nil {
+		return nil, nil, err
+	}
+	excluded, err := matchCases(cases, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, kase := range cases {
+		keep := !excluded[i]
+		if len(include) > 0 && len(exclude) == 0 {
+			keep = false
+		}
+		if included[i] {
+			keep = true
+		}
+		if keep {
+			kept = append(kept, kase)
+		} else {
+			dropped = append(dropped, kase)
+		}
+	}
+	return kept, dropped, nil
+}
+
+// casesFile holds the case patterns read from a file
+type casesFile struct {
+	name     string
+	patterns []string
+	lines    []int // line number of each pattern
+}
+
+// readCasesFile reads case patterns from a file, one per line.
+// Empty lines and lines starting with # are ignored. The file is opened with
+// the permissions of the user, so that it cannot be a file of the setgid group.
+func readCasesFile(filename string) (*casesFile, error) {
+	cf := &casesFile{name: filename}
+	err := asUser(func() error {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for lineno := 1; scanner.Scan(); lineno++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			cf.patterns = append(cf.patterns, line)
+			cf.lines = append(cf.lines, lineno)
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cf, nil
+}
+
+// check returns an error naming the first line with a bad pattern or a pattern
+// that matches no case. The lines are not quoted, so that the errors cannot
+// show the content of a file that is not a list of cases.
+func (cf *casesFile) check(cases []string) error {
+	for i, pattern := range cf.patterns {
+		if _, err := matchCases(cases, []string{pattern}); err != nil {
+			return fmt.Errorf("%s:%d: bad case pattern or no matching case", cf.name, cf.lines[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package judge
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var filterTestCases = []string{"01", "02", "03", "large01", "large02"}
+
+func TestFilterCasesExclude(t *testing.T) {
+	kept, dropped, err := filterCases(filterTestCases, nil, []string{"large*", "0[1-2]"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"03"}, kept)
+	assert.Equal(t, []string{"01", "02", "large01", "large02"}, dropped)
+}
+
+func TestFilterCasesInclude(t *testing.T) {
+	kept, _, err := filterCases(filterTestCases, []string{"0[1,3]"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01", "03"}, kept)
+
+	kept, _, err = filterCases(filterTestCases, []string{"large02"}, []string{"large*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01", "02", "03", "large02"}, kept)
+}
+
+func TestFilterCasesNoMatch(t *testing.T) {
+	_, _, err := filterCases(filterTestCases, []string{"0[3-4]"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"04" matches no case`)
+	assert.Contains(t, err.Error(), "01 02 03 large01 large02")
+}
+
+func TestReadCasesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "judge-filter-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cases")
+	writeFile(t, filename, "# small cases\n0[1-2]\n\n  large01  \nsecret\n", 0644)
+
+	cf, err := readCasesFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0[1-2]", "large01", "secret"}, cf.patterns)
+	assert.Equal(t, []int{2, 4, 5}, cf.lines)
+
+	// the line is named but not quoted
+	err = cf.check(filterTestCases)
+	assert.EqualError(t, err, filename+":5: bad case pattern or no matching case")
+	cf.patterns, cf.lines = cf.patterns[:2], cf.lines[:2]
+	assert.NoError(t, cf.check(filterTestCases))
+}
+
+func TestReadCasesFileSetgid(t *testing.T) {
+	if dir, ok := setgidChild(); ok {
+		secret := filepath.Join(dir, "secret")
+		// the setgid group can read the file
+		_, err := ioutil.ReadFile(secret)
+		require.NoError(t, err)
+
+		_, err = readCasesFile(secret)
+		require.Error(t, err)
+		assert.True(t, os.IsPermission(err), "%v", err)
+		assert.NotContains(t, err.Error(), "s3cret")
+
+		cf, err := readCasesFile(filepath.Join(dir, "cases"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"01"}, cf.patterns)
+		return
+	}
+
+	dir := setgidDir(t)
+	secret := filepath.Join(dir, "secret")
+	writeFile(t, secret, "s3cret\n", 0440)
+	require.NoError(t, os.Chown(secret, 0, setgidGroup))
+	writeFile(t, filepath.Join(dir, "cases"), "01\n", 0644)
+	runSetgid(t, dir)
+}
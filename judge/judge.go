@@ -3,15 +3,12 @@ package judge
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"os/exec"
-	"os/signal"
-	"os/user"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -19,34 +16,10 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/NTHU-lsalab/sb"
 	"github.com/NTHU-lsalab/sb/colors"
 	"github.com/NTHU-lsalab/sb/pb"
-
-	"google.golang.org/grpc"
 )
 
-var username string
-var home string
-
-func init() {
-	log.SetFlags(0)
-	currentUser, err := user.Current()
-	if err != nil {
-		log.Fatalf("cannot get user: %v", err)
-	}
-	username = currentUser.Username
-	home = currentUser.HomeDir
-}
-
-func tempdir() string {
-	dir, err := ioutil.TempDir(home, ".judge.*")
-	if err != nil {
-		log.Fatalf("failed to create temporary directory: %v", err)
-	}
-	return dir
-}
-
 type red string
 
 func (r red) String() string {
@@ -61,50 +34,64 @@ func copyFile(src, dst string) error {
 	defer srcFile.Close()
 	dstFile, err := os.Create(dst)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	defer dstFile.Close()
 	_, err = io.Copy(dstFile, srcFile)
 	if err != nil {
-		panic(err)
+		dstFile.Close()
+		return err
+	}
+	return dstFile.Close()
+}
+
+// inDir returns the path of a file named relative to dir, or the name itself
+// if dir is empty or the name is absolute
+func inDir(dir, name string) string {
+	if dir == "" || filepath.IsAbs(name) {
+		return name
 	}
-	return nil
+	return filepath.Join(dir, name)
 }
 
-func lookForCopy(ctx context.Context, filename, fallback, targetdir string) bool {
-	err := copyFile(filename, filepath.Join(targetdir, filename))
+func lookForCopy(ctx context.Context, logger *log.Logger, srcdir, filename, fallback, targetdir string) bool {
+	err := copyFile(inDir(srcdir, filename), filepath.Join(targetdir, filename))
 	if err == nil {
-		log.Printf("Looking for %s: %s\n", filename, colors.Green("OK"))
+		logger.Printf("Looking for %s: %s\n", filename, colors.Green("OK"))
 		return true
 	} else if fallback == "" {
-		log.Printf("Looking for %s: %s\n", filename, colors.Red("Not Found"))
+		logger.Printf("Looking for %s: %s\n", filename, colors.Red("Not Found"))
 		return false
 	} else {
-		log.Printf("Looking for %s: %s\n", filename, colors.Yellow("Not Found"))
-		err = copyFile(fallback, filepath.Join(targetdir, filename))
+		logger.Printf("Looking for %s: %s\n", filename, colors.Yellow("Not Found"))
+		err = copyFile(inDir(srcdir, fallback), filepath.Join(targetdir, filename))
 		if err == nil {
-			log.Printf("Using fallback: %s: %s\n", fallback, colors.Green("OK"))
+			logger.Printf("Using fallback: %s: %s\n", fallback, colors.Green("OK"))
 			return true
 		}
-		log.Printf("Using fallback: %s: %s\n", fallback, colors.Red("Failed"))
+		logger.Printf("Using fallback: %s: %s\n", fallback, colors.Red("Failed"))
 		return false
 	}
 }
 
-func printCommand(command []string) {
+func printCommand(logger *log.Logger, command []string) {
 	args := []interface{}{"Running:"}
 	for _, part := range command {
 		args = append(args, part)
 	}
-	log.Println(args...)
+	logger.Println(args...)
 }
 
-func compile(ctx context.Context, rule Rule, dir string) bool {
+// buildArgs returns the command used to build the target in dir
+func buildArgs(dir, target string) []string {
+	return []string{"/usr/bin/make", "-C", dir, target}
+}
+
+func copySources(ctx context.Context, rule Rule, dir string) bool {
 	for _, filename := range rule.Mandantory {
 		if ctx.Err() != nil {
 			return false
 		}
-		if !lookForCopy(ctx, filename, "", dir) {
+		if !lookForCopy(ctx, rule.Log, rule.Dir, filename, "", dir) {
 			return false
 		}
 	}
@@ -112,22 +99,28 @@ func compile(ctx context.Context, rule Rule, dir string) bool {
 		if ctx.Err() != nil {
 			return false
 		}
-		if !lookForCopy(ctx, pair.Name, pair.Fallback, dir) {
+		if !lookForCopy(ctx, rule.Log, rule.Dir, pair.Name, pair.Fallback, dir) {
 			return false
 		}
 	}
-	cmd := exec.CommandContext(ctx, "/usr/bin/make", "-C", dir, rule.Target)
-	printCommand(cmd.Args)
-	cmd.Stdout = os.Stderr
-	cmd.Stderr = os.Stderr
+	return true
+}
+
+func compile(ctx context.Context, rule Rule, dir string) bool {
+	args := buildArgs(dir, rule.Target)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = rule.Dir
+	printCommand(rule.Log, cmd.Args)
+	cmd.Stdout = rule.Stderr
+	cmd.Stderr = rule.Stderr
 	err := cmd.Run()
 	if err != nil {
-		log.Printf("Cannot compile executable\n")
+		rule.Log.Printf("Cannot compile executable\n")
 		return false
 	}
 	_, err = os.Stat(filepath.Join(dir, rule.Target))
 	if err != nil {
-		log.Printf("Compilation succeed but executable wasn't generated\n")
+		rule.Log.Printf("Compilation succeed but executable wasn't generated\n")
 		return false
 	}
 	// TODO: check that the output is executable
@@ -143,41 +136,100 @@ type OptionalFile struct {
 
 // Rule defines a rule of a given homework
 type Rule struct {
-	Target      string
-	Mandantory  []string
-	Optional    []OptionalFile
-	Runner      string
-	SkipCompile bool
-	MedianOf    int
-	Debug       bool
+	Dir          string // directory of the source files, in which the runners start. the working directory if empty
+	Target       string
+	Mandantory   []string
+	Optional     []OptionalFile
+	Runner       string
+	SkipCompile  bool
+	MedianOf     int
+	Debug        bool
+	Cgroup       *cgroupRoot // account runners' resource usage in cgroups if not nil
+	Limits       *pb.Limits
+	BuildCache   *buildCache // reuse executables built from the same sources if not nil
+	Timing       *Timing     // run cases adaptively until the timing is stable if not nil
+	TimingPolicy string      // the time submitted for cases run multiple times
+
+	PreviousTimes map[string]float64 // times of the cases in previous runs, used for scheduling
+	ConfigOrder   bool               // run the cases in config order instead of longest first
+
+	FailFast     bool          // stop judging after the first failing case
+	Retries      int           // retry runs with internal errors up to this many times
+	RetryBackoff time.Duration // delay before the first retry, doubled for each further retry
+
+	Log     *log.Logger // progress messages
+	Stderr  io.Writer   // output of the compiler, and of the runners in debug mode
+	TempDir string      // parent of the build directory
 }
 
+// verdictInternalError is the verdict of runs that failed because of the judge or the runner
+// rather than the submission. Only these runs are retried.
+const verdictInternalError = "internal error"
+
 // judgeRequest is a request for judgeing a single case
 type judgeRequest struct {
 	CaseID     int
 	CaseName   string
 	Executable string
 	Runner     string
+	Dir        string // working directory of the runner
 	Debug      bool
+	Run        int // index of the run when a case is run multiple times
+	Cgroup     *cgroupRoot
+	Limits     *pb.Limits
+	Log        *log.Logger
+	Stderr     io.Writer
 }
 
 // judgeResult is the result of judging a single case
 type judgeResult struct {
-	CaseID   int    `json:"-"`
-	CaseName string `json:"-"`
-	Passed   bool
-	Time     float64
-	Verdict  string
-	Details  string
+	CaseID            int
+	CaseName          string
+	Run               int
+	Passed            bool
+	Time              float64
+	Verdict           string
+	Details           string
+	StructuredDetails string
+	Memory            int64
+	CPUTime           float64
+	Metrics           map[string]float64
+	Node              string
+	Usage             resourceUsage // measured by the judge
+	Timing            *pb.TimingStats
 }
 
 func (jr judgeResult) toProto() *pb.Result {
 	return &pb.Result{
-		Case:    jr.CaseName,
-		Passed:  jr.Passed,
-		Time:    jr.Time,
-		Verdict: jr.Verdict,
+		Case:              jr.CaseName,
+		Passed:            jr.Passed,
+		Time:              jr.Time,
+		Verdict:           jr.Verdict,
+		Details:           jr.Details,
+		StructuredDetails: jr.StructuredDetails,
+		Memory:            jr.Memory,
+		CpuTime:           jr.CPUTime,
+		Metrics:           jr.Metrics,
+		Node:              jr.Node,
+		RunnerUsage:       jr.Usage.toProto(),
+		Timing:            jr.Timing,
+	}
+}
+
+func (jr judgeResult) formatMetrics() string {
+	if len(jr.Metrics) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(jr.Metrics))
+	for name := range jr.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%g", name, jr.Metrics[name])
 	}
+	return " [" + strings.Join(parts, " ") + "]"
 }
 
 func (jr judgeResult) formatDescription() string {
@@ -187,27 +239,107 @@ func (jr judgeResult) formatDescription() string {
 	} else {
 		verdict = colors.Red(jr.Verdict)
 	}
-	if jr.Details == "" {
+	verdict += jr.formatMetrics() + formatTiming(jr.Timing)
+	details := jr.Details
+	if details == "" {
+		details = jr.StructuredDetails
+	}
+	if details == "" {
 		return verdict
 	}
-	return verdict + ": " + jr.Details
+	return verdict + ": " + details
+}
+
+// noteRetries records the internal errors of the retried attempts in the details
+func (jr *judgeResult) noteRetries(errors []string) {
+	note := fmt.Sprintf("retried %d times after internal errors: %s", len(errors), strings.Join(errors, "; "))
+	if len(errors) == 1 {
+		note = "retried once after an internal error: " + errors[0]
+	}
+	jr.addDetails(note)
+}
+
+// addDetails appends a note of the judge to the details given by the runner
+func (jr *judgeResult) addDetails(note string) {
+	if jr.Details == "" {
+		jr.Details = note
+	} else {
+		jr.Details += "\n" + note
+	}
+}
+
+// peakMemory returns the peak memory usage in KiB reported by the runner or measured by the judge
+func (jr judgeResult) peakMemory() int64 {
+	if jr.Memory > jr.Usage.MaxRSS {
+		return jr.Memory
+	}
+	return jr.Usage.MaxRSS
 }
 
 func judgeCase(ctx context.Context, jr judgeRequest) judgeResult {
-	var cmd *exec.Cmd
+	args := []string{jr.CaseName, jr.Executable}
 	if jr.Debug {
-		cmd = exec.Command(jr.Runner, "--debug", jr.CaseName, jr.Executable)
-		cmd.Stderr = os.Stderr
-	} else {
-		cmd = exec.Command(jr.Runner, jr.CaseName, jr.Executable)
+		args = append([]string{"--debug"}, args...)
 	}
 	output := bytes.NewBuffer(nil)
-	cmd.Stdout = output
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true,
+	newCmd := func() *exec.Cmd {
+		var cmd *exec.Cmd
+		if needsHelper(jr.Limits) {
+			cmd = helperCommand(jr.Limits, jr.Runner, args...)
+		} else {
+			cmd = exec.Command(jr.Runner, args...)
+		}
+		cmd.Dir = jr.Dir
+		if jr.Debug {
+			cmd.Stderr = jr.Stderr
+		}
+		cmd.Stdout = output
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Setpgid: true,
+		}
+		return cmd
+	}
+	cmd := newCmd()
+	// the runner starts in its cgroup, so that its children cannot escape the
+	// accounting and the memory limit
+	var cg *runnerCgroup
+	if jr.Cgroup != nil {
+		var err error
+		cg, err = jr.Cgroup.newRunnerCgroup(fmt.Sprintf("case-%d-%d", jr.CaseID, jr.Run))
+		if err == nil && jr.Limits.GetMemory() > 0 {
+			err = cg.setMemoryMax(jr.Limits.Memory * 1024 * 1024)
+			if err != nil {
+				jr.Log.Printf("Failed to limit memory of %s: %v", jr.CaseName, err)
+				err = nil
+			}
+		}
+		if err == nil {
+			var dir *os.File
+			dir, err = cg.open()
+			if err == nil {
+				defer dir.Close()
+				cmd.SysProcAttr.UseCgroupFD = true
+				cmd.SysProcAttr.CgroupFD = int(dir.Fd())
+			} else {
+				cg.remove()
+				cg = nil
+			}
+		}
+		if err != nil {
+			jr.Log.Printf("Failed to account %s in cgroup: %v", jr.CaseName, err)
+		}
 	}
 	t0 := time.Now()
 	err := cmd.Start()
+	if err != nil && cg != nil {
+		// starting in a cgroup needs Linux 5.7
+		jr.Log.Printf("Failed to start %s in cgroup, running it without: %v", jr.CaseName, err)
+		cg.remove()
+		cg = nil
+		cmd = newCmd()
+		t0 = time.Now()
+		err = cmd.Start()
+	}
 	extraDetail := func() string {
 		if !jr.Debug {
 			return ""
@@ -219,17 +351,17 @@ func judgeCase(ctx context.Context, jr judgeRequest) judgeResult {
 	}
 	if err != nil {
 		return judgeResult{
-			jr.CaseID,
-			jr.CaseName,
-			false,
-			time.Now().Sub(t0).Seconds(),
-			"internal error",
-			fmt.Sprintf("could not start runner: %v%s", err, extraDetail()),
+			CaseID:   jr.CaseID,
+			CaseName: jr.CaseName,
+			Passed:   false,
+			Time:     time.Now().Sub(t0).Seconds(),
+			Verdict:  verdictInternalError,
+			Details:  fmt.Sprintf("could not start runner: %v%s", err, extraDetail()),
 		}
 	}
 	pgid, err := syscall.Getpgid(cmd.Process.Pid)
 	if err != nil {
-		log.Println("Getpgid failed")
+		jr.Log.Println("Getpgid failed")
 		pgid = cmd.Process.Pid
 	}
 	cmdDone := make(chan struct{})
@@ -247,83 +379,140 @@ func judgeCase(ctx context.Context, jr judgeRequest) judgeResult {
 	}()
 	err = cmd.Wait()
 	close(cmdDone)
+	usage := rusageOf(cmd.ProcessState)
+	oomKilled := false
+	if cg != nil {
+		oomKilled = cg.oomKilled()
+		cgUsage, cgErr := cg.usage()
+		if cgErr == nil {
+			usage.UserTime = cgUsage.UserTime
+			usage.SysTime = cgUsage.SysTime
+			if cgUsage.MaxRSS > 0 {
+				usage.MaxRSS = cgUsage.MaxRSS
+			}
+		}
+		cg.remove()
+	}
 	if err != nil {
 		return judgeResult{
-			jr.CaseID,
-			jr.CaseName,
-			false,
-			time.Now().Sub(t0).Seconds(),
-			"internal error",
-			fmt.Sprintf("could not execute runner: %v%s", err, extraDetail()),
+			CaseID:   jr.CaseID,
+			CaseName: jr.CaseName,
+			Passed:   false,
+			Time:     time.Now().Sub(t0).Seconds(),
+			Verdict:  verdictInternalError,
+			Details:  fmt.Sprintf("could not execute runner: %v%s", err, extraDetail()),
+			Usage:    usage,
 		}
 	}
 	result := judgeResult{
 		CaseID:   jr.CaseID,
 		CaseName: jr.CaseName,
+		Usage:    usage,
 	}
-	err = json.Unmarshal(output.Bytes(), &result)
+	err = parseRunnerOutput(output.Bytes(), &result)
 	if err != nil {
 		errMsg := fmt.Sprintf("%v", err)
 		if jr.Debug {
 			errMsg += ": " + string(output.Bytes())
 		}
 		return judgeResult{
-			jr.CaseID,
-			jr.CaseName,
-			false,
-			time.Now().Sub(t0).Seconds(),
-			"internal error",
-			fmt.Sprintf("runner output invalid: %v%s", errMsg, extraDetail()),
+			CaseID:   jr.CaseID,
+			CaseName: jr.CaseName,
+			Passed:   false,
+			Time:     time.Now().Sub(t0).Seconds(),
+			Verdict:  verdictInternalError,
+			Details:  fmt.Sprintf("runner output invalid: %v%s", errMsg, extraDetail()),
+			Usage:    usage,
 		}
 	}
+	if memoryLimit := jr.Limits.GetMemory() * 1024; memoryLimit > 0 && (oomKilled || result.peakMemory() > memoryLimit) {
+		result.Passed = false
+		result.Verdict = "memory limit exceeded"
+		result.addDetails(fmt.Sprintf("peak memory %s, limit %s", formatKiB(result.peakMemory()), formatKiB(memoryLimit)))
+	}
 	result.Details += extraDetail()
 	return result
 }
 
-func removeAllVerbose(directory string) {
-	log.Println("Removing temporary directory", directory)
+func removeAllVerbose(logger *log.Logger, directory string) {
+	logger.Println("Removing temporary directory", directory)
 	os.RemoveAll(directory)
 }
 
-func judge(ctx context.Context, rule Rule, cases []string) []*pb.Result {
+func judge(ctx context.Context, rule Rule, cases []string, report *Report) {
+	report.Cases = make([]CaseReport, len(cases))
+	for i, casename := range cases {
+		report.Cases[i].Case = casename
+	}
+
 	var exe string
 	if rule.SkipCompile {
 		exe = rule.Target
+		report.Build = BuildSkipped
 	} else {
-		buildDir := tempdir()
-		defer removeAllVerbose(buildDir)
-		if !compile(ctx, rule, buildDir) {
-			return nil
+		buildDir, err := ioutil.TempDir(rule.TempDir, ".judge.*")
+		if err != nil {
+			rule.Log.Printf("Failed to create temporary directory: %v", err)
+			report.Build = BuildFailed
+			return
+		}
+		defer removeAllVerbose(rule.Log, buildDir)
+		if !copySources(ctx, rule, buildDir) {
+			report.Build = BuildFailed
+			return
+		}
+		var key string
+		var cached bool
+		if rule.BuildCache != nil {
+			var err error
+			key, err = buildKey(rule, buildDir)
+			if err != nil {
+				rule.Log.Printf("Cannot hash the sources for the build cache: %v", err)
+			} else {
+				exe, cached = rule.BuildCache.lookup(key, rule.Target)
+			}
+		}
+		if cached {
+			rule.Log.Printf("Sources unchanged, using cached executable %s", exe)
+			report.Build = BuildCached
+		} else {
+			if !compile(ctx, rule, buildDir) {
+				report.Build = BuildFailed
+				return
+			}
+			exe = filepath.Join(buildDir, rule.Target)
+			report.Build = BuildOK
+			if key != "" {
+				err := rule.BuildCache.store(key, exe, rule.Target)
+				if err != nil {
+					rule.Log.Printf("Failed to cache the executable: %v", err)
+				}
+			}
 		}
-		exe = filepath.Join(buildDir, rule.Target)
 	}
 
+	// cancelled when judging stops early, killing the running cases
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	requests := make(chan judgeRequest)
 	responses := make(chan judgeResult)
+	retries := make(chan judgeRequest)
+	defer close(requests)
 
-	for i := 0; i < 4; i++ {
+	for i := 0; i < numWorkers; i++ {
 		go func() {
 			for r := range requests {
-				responses <- judgeCase(ctx, r)
+				result := judgeCase(ctx, r)
+				result.Run = r.Run
+				select {
+				case responses <- result:
+				case <-ctx.Done():
+				}
 			}
 		}()
 	}
 
-	go func() {
-		for caseID, casename := range cases {
-			for i := 0; i < rule.MedianOf; i++ {
-				requests <- judgeRequest{
-					CaseID:     caseID,
-					CaseName:   casename,
-					Executable: exe,
-					Runner:     rule.Runner,
-					Debug:      rule.Debug,
-				}
-			}
-		}
-		close(requests)
-	}()
-
 	caseWidth := 0
 	for _, casename := range cases {
 		if caseWidth < len(casename) {
@@ -331,175 +520,226 @@ func judge(ctx context.Context, rule Rule, cases []string) []*pb.Result {
 		}
 	}
 
+	var eta func() string
 	printResult := func(result judgeResult, hint string) {
-		log.Printf("%*s%s %7.2f   %s",
+		rule.Log.Printf("%*s%s %7.2f %7.2f %7s   %s%s",
 			caseWidth,
 			result.CaseName,
 			hint,
 			result.Time,
+			result.Usage.CPUTime(),
+			formatKiB(result.Usage.MaxRSS),
 			result.formatDescription(),
+			eta(),
 		)
 	}
 
-	result := make([]*pb.Result, 0, len(cases))
-	buffer := make([][]judgeResult, len(cases))
-	medNumWidth := len(strconv.Itoa(rule.MedianOf))
-	I := len(cases) * rule.MedianOf
-	for i := 0; i < I; i++ {
-		select {
-		case <-ctx.Done():
-			return result
-		case response := <-responses:
-			if rule.MedianOf > 1 {
-				buffer[response.CaseID] = append(buffer[response.CaseID], response)
-				printResult(response, fmt.Sprintf("#%0*d", medNumWidth, len(buffer[response.CaseID])))
-				if len(buffer[response.CaseID]) == rule.MedianOf {
-					runs := buffer[response.CaseID]
-					sort.Slice(runs, func(i, j int) bool {
-						if runs[i].Passed == runs[j].Passed {
-							return runs[i].Time < runs[j].Time
-						}
-						return runs[i].Passed
-					})
-					result = append(result, runs[rule.MedianOf/2].toProto())
-					printResult(runs[rule.MedianOf/2], fmt.Sprintf(" %*s", medNumWidth, ""))
-				}
-			} else {
-				printResult(response, "")
-				result = append(result, response.toProto())
-			}
+	// caseState tracks the runs of a case
+	type caseState struct {
+		runs    []judgeResult    // measured runs
+		warmups int              // finished warmup runs
+		pending int              // requested runs not finished yet
+		next    int              // index of the next run
+		errors  map[int][]string // details of the retried internal errors of each run
+	}
+	states := make([]caseState, len(cases))
+	var queue []judgeRequest
+	newRequest := func(caseID, run int) judgeRequest {
+		return judgeRequest{
+			CaseID:     caseID,
+			CaseName:   cases[caseID],
+			Executable: exe,
+			Runner:     rule.Runner,
+			Dir:        rule.Dir,
+			Debug:      rule.Debug,
+			Run:        run,
+			Cgroup:     rule.Cgroup,
+			Limits:     rule.Limits,
+			Log:        rule.Log,
+			Stderr:     rule.Stderr,
+		}
+	}
+	enqueue := func(caseID, n int) {
+		for i := 0; i < n; i++ {
+			queue = append(queue, newRequest(caseID, states[caseID].next))
+			states[caseID].next++
+			states[caseID].pending++
 		}
 	}
-	return result
-}
 
-// Options is passed to MainOptions
-type Options struct {
-	Chdir        string
-	ExcludeCases []string // exclude these cases
-	IncludeCases []string // include these cases
-	AsUser       string   // run the judge as this user. privileged.
-	RuleFile     string   // use the rules defined in the config file instead of argv[0]. privileged.
-	Server       string   // the judge server
-	Homework     string   // the name of the homework
-	Bin          string   // skip compiling and use the given binary. privileged.
-	MedianOf     int      // run each case multiple times and pick the median as the result
-	Debug        bool     // output debug messages
-}
+	maxRuns := rule.MedianOf
+	warmup := 0
+	if rule.Timing != nil {
+		maxRuns = rule.Timing.MaxRuns
+		warmup = rule.Timing.Warmup
+	}
+	runNumWidth := len(strconv.Itoa(maxRuns))
 
-// MainOptions runs the judge with the given options
-func MainOptions(options *Options) {
-	if options.Chdir != "" {
-		err := os.Chdir(options.Chdir)
-		if err != nil {
-			log.Fatalf("failed to chdir: %v", err)
-		}
+	order := make([]int, len(cases))
+	for i := range order {
+		order[i] = i
 	}
-	if !strings.HasPrefix(options.Server, "unix://") && strings.ContainsRune(options.Server, '/') {
-		options.Server = "unix://" + options.Server
+	estimates := estimateTimes(cases, rule.PreviousTimes)
+	if estimates != nil && !rule.ConfigOrder {
+		order = longestFirst(estimates)
 	}
-	conn, err := grpc.Dial(options.Server,
-		grpc.WithInsecure(), grpc.WithBlock(), grpc.FailOnNonTempDialError(true))
-	if err != nil {
-		log.Fatalf("failed to connect: %v", err)
+	// eta estimates the remaining time from the planned runs of the unfinished cases
+	eta = func() string {
+		if estimates == nil {
+			return ""
+		}
+		var jobs []float64
+		for _, caseID := range order {
+			if report.Cases[caseID].Result != nil {
+				continue
+			}
+			st := states[caseID]
+			planned := rule.MedianOf
+			if rule.Timing != nil {
+				planned = warmup + rule.Timing.MinRuns
+			}
+			remaining := planned - st.warmups - len(st.runs)
+			if remaining < 1 {
+				remaining = 1
+			}
+			for i := 0; i < remaining; i++ {
+				jobs = append(jobs, estimates[caseID])
+			}
+		}
+		return fmt.Sprintf("   [ETA %s]", formatETA(estimateMakespan(jobs, numWorkers)))
+	}
+	if estimates != nil {
+		rule.Log.Printf("Judging %d cases%s", len(cases), eta())
 	}
-	defer conn.Close()
-
-	ctx, cancel := context.WithCancel(context.Background())
-
-	interrupted := make(chan os.Signal, 1)
-	go func() {
-		<-interrupted
-		log.Println("Cleaning up...")
-		cancel()
-	}()
-	signal.Notify(interrupted, os.Interrupt)
 
-	c := pb.NewScoreboardClient(conn)
-	var hw *pb.Homework
-	if options.RuleFile != "" {
-		if sb.Privileged() {
-			hw = sb.LoadHomework(options.RuleFile)
+	for _, caseID := range order {
+		if warmup > 0 {
+			enqueue(caseID, warmup)
+		} else if rule.Timing != nil {
+			enqueue(caseID, rule.Timing.MinRuns)
 		} else {
-			log.Fatal("Cannot specify rule file when not privileged")
-		}
-	} else {
-		hw, err = c.QueryHomework(ctx, &pb.QueryHomeworkRequest{
-			Name: options.Homework,
-		})
-		if err != nil {
-			log.Fatalf("failed to get homework %s: %v", options.Homework, err)
+			enqueue(caseID, rule.MedianOf)
 		}
 	}
 
-	if options.AsUser != username && !sb.Privileged() {
-		log.Fatal("Cannot run as other user when not privileged")
+	failed := false
+	finish := func(caseID int) {
+		runs := states[caseID].runs
+		if len(runs) == 1 {
+			report.Cases[caseID].Result = runs[0].toProto()
+			failed = failed || !runs[0].Passed
+			return
+		}
+		for _, run := range runs {
+			report.Cases[caseID].Runs = append(report.Cases[caseID].Runs, run.toProto())
+		}
+		result := summarizeRuns(runs, rule.TimingPolicy, rule.Timing != nil)
+		report.Cases[caseID].Result = result.toProto()
+		printResult(result, fmt.Sprintf(" %*s", runNumWidth, ""))
+		failed = failed || !result.Passed
+	}
+
+	// delayed counts the retries waiting for their backoff
+	delayed := 0
+	retry := func(response judgeResult) bool {
+		st := &states[response.CaseID]
+		errors := st.errors[response.Run]
+		if response.Verdict != verdictInternalError || len(errors) >= rule.Retries {
+			return false
+		}
+		if st.errors == nil {
+			st.errors = make(map[int][]string)
+		}
+		st.errors[response.Run] = append(errors, response.Details)
+		delay := rule.RetryBackoff << uint(len(errors))
+		rule.Log.Printf("%*s %s: %s, retrying in %s (%d/%d)",
+			caseWidth, response.CaseName, response.Verdict, response.Details, delay, len(errors)+1, rule.Retries)
+		delayed++
+		r := newRequest(response.CaseID, response.Run)
+		go func() {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case retries <- r:
+			case <-ctx.Done():
+			}
+		}()
+		return true
 	}
 
-	cases := make([]string, 0, len(hw.Cases))
-	for _, kase := range hw.Cases {
-		keep := true
-		for _, ex := range options.ExcludeCases {
-			if kase == ex {
-				keep = false
-			}
+	handle := func(response judgeResult) {
+		st := &states[response.CaseID]
+		if retry(response) {
+			return
 		}
-		if len(options.IncludeCases) > 0 && len(options.ExcludeCases) == 0 {
-			keep = false
+		if errors := st.errors[response.Run]; len(errors) > 0 {
+			response.noteRetries(errors)
 		}
-		for _, in := range options.IncludeCases {
-			if kase == in {
-				keep = true
+		st.pending--
+		if response.Run < warmup {
+			st.warmups++
+			printResult(response, fmt.Sprintf("w%0*d", runNumWidth, st.warmups))
+			if st.warmups == warmup {
+				enqueue(response.CaseID, rule.Timing.MinRuns)
 			}
+			return
 		}
-		if keep {
-			cases = append(cases, kase)
+		st.runs = append(st.runs, response)
+		if maxRuns > 1 {
+			printResult(response, fmt.Sprintf("#%0*d", runNumWidth, len(st.runs)))
 		} else {
-			log.Println("Excluded", kase)
+			printResult(response, "")
 		}
-	}
-
-	if options.MedianOf%2 == 0 {
-		log.Fatal("Refusing to pick a median from a even number of runs")
-	}
-
-	rule := Rule{
-		Target:   hw.Target,
-		Runner:   hw.Runner,
-		Optional: make([]OptionalFile, len(hw.Files)),
-		MedianOf: options.MedianOf,
-		Debug:    options.Debug,
-	}
-
-	for i, source := range hw.Files {
-		rule.Optional[i].Name = source.Name
-		rule.Optional[i].Fallback = source.Fallback
-	}
-
-	if options.Bin != "" {
-		if sb.Privileged() {
-			rule.SkipCompile = true
-			rule.Target = options.Bin
-		} else {
-			log.Println("Cannot skip compiling when not privileged")
+		if st.pending > 0 {
+			return
 		}
+		if rule.Timing == nil {
+			finish(response.CaseID)
+			return
+		}
+		var times []float64
+		for _, run := range st.runs {
+			if !run.Passed {
+				finish(response.CaseID)
+				return
+			}
+			times = append(times, run.Time)
+		}
+		stats := timingStats(times)
+		if len(st.runs) >= rule.Timing.MaxRuns || stats.RelativeCi <= rule.Timing.TargetCI {
+			finish(response.CaseID)
+			return
+		}
+		enqueue(response.CaseID, 1)
 	}
 
-	result := judge(ctx, rule, cases)
-	if len(result) == 0 {
-		return
-	}
-	cancel()
-
-	sbCtx, sbCancel := context.WithTimeout(context.Background(), time.Second*3)
-	r, err := c.Submit(sbCtx, &pb.UserSubmission{
-		User:     options.AsUser,
-		Homework: hw.Name,
-		Results:  result,
-	})
-	if err != nil {
-		log.Fatalf("failed to submit results to scoreboard: %v", err)
+	outstanding := 0
+	for len(queue) > 0 || outstanding > 0 || delayed > 0 {
+		var send chan<- judgeRequest
+		var next judgeRequest
+		if len(queue) > 0 {
+			send = requests
+			next = queue[0]
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case send <- next:
+			queue = queue[1:]
+			outstanding++
+		case r := <-retries:
+			delayed--
+			queue = append([]judgeRequest{r}, queue...)
+		case response := <-responses:
+			outstanding--
+			handle(response)
+			if failed && rule.FailFast {
+				rule.Log.Println("Stopping after the first failing case")
+				return
+			}
+		}
 	}
-	log.Println("Scoreboard:", r.Message)
-	sbCancel()
 }
@@ -0,0 +1,34 @@
+package judge
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// saveReceipt saves the reply of a submission with its receipt, which can be
+// verified with sb --verify-receipt, and returns the file name
+func saveReceipt(reply *pb.SubmissionReply) (filename string, err error) {
+	err = asUser(func() error {
+		dir, err := cacheDir()
+		if err != nil {
+			return err
+		}
+		record := reply.Record
+		filename = filepath.Join(dir, "receipts", filepath.Base(record.Homework), filepath.Base(record.Id)+".json")
+		err = os.MkdirAll(filepath.Dir(filename), 0755)
+		if err != nil {
+			return err
+		}
+		b, err := protojson.MarshalOptions{Multiline: true}.Marshal(reply)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filename, b, 0644)
+	})
+	return filename, err
+}
@@ -0,0 +1,274 @@
+package judge
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Build status of a Report
+const (
+	BuildOK      = "ok"
+	BuildCached  = "cached" // the executable of the same sources was reused
+	BuildFailed  = "failed"
+	BuildSkipped = "skipped"
+)
+
+// Report is the outcome of judging a homework
+type Report struct {
+	Homework     string
+	User         string
+	Build        string
+	Cases        []CaseReport
+	Submitted    bool
+	Scoreboard   string // reply message of the scoreboard
+	SubmissionID string // id of the submission given by the scoreboard
+	SubmitError  string
+}
+
+// CaseReport is the outcome of judging a single case
+type CaseReport struct {
+	Case   string
+	Result *pb.Result   // nil if the case was not judged
+	Runs   []*pb.Result // individual runs when the case is run multiple times
+}
+
+// Results returns the results of the judged cases
+func (r *Report) Results() []*pb.Result {
+	results := make([]*pb.Result, 0, len(r.Cases))
+	for _, c := range r.Cases {
+		if c.Result != nil {
+			results = append(results, c.Result)
+		}
+	}
+	return results
+}
+
+// Output formats of a Report
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+	FormatTAP   = "tap"
+)
+
+// WriteReport writes the report in the given format.
+// FormatText writes nothing, as the human readable output is logged while judging.
+func WriteReport(w io.Writer, format string, r *Report) error {
+	switch format {
+	case FormatText, "":
+		return nil
+	case FormatJSON:
+		return writeJSON(w, r)
+	case FormatJUnit:
+		return writeJUnit(w, r)
+	case FormatTAP:
+		return writeTAP(w, r)
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+var resultMarshaler = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+type jsonResult struct {
+	*pb.Result
+}
+
+func (r jsonResult) MarshalJSON() ([]byte, error) {
+	if r.Result == nil {
+		return []byte("null"), nil
+	}
+	return resultMarshaler.Marshal(r.Result)
+}
+
+func writeJSON(w io.Writer, r *Report) error {
+	type jsonCase struct {
+		Case   string       `json:"case"`
+		Result jsonResult   `json:"result"`
+		Runs   []jsonResult `json:"runs,omitempty"`
+	}
+	out := struct {
+		Homework     string     `json:"homework"`
+		User         string     `json:"user"`
+		Build        string     `json:"build"`
+		Cases        []jsonCase `json:"cases"`
+		Submitted    bool       `json:"submitted"`
+		Scoreboard   string     `json:"scoreboard,omitempty"`
+		SubmissionID string     `json:"submission_id,omitempty"`
+		SubmitError  string     `json:"submit_error,omitempty"`
+	}{
+		Homework:     r.Homework,
+		User:         r.User,
+		Build:        r.Build,
+		Cases:        make([]jsonCase, len(r.Cases)),
+		Submitted:    r.Submitted,
+		Scoreboard:   r.Scoreboard,
+		SubmissionID: r.SubmissionID,
+		SubmitError:  r.SubmitError,
+	}
+	for i, c := range r.Cases {
+		out.Cases[i] = jsonCase{Case: c.Case, Result: jsonResult{c.Result}}
+		for _, run := range c.Runs {
+			out.Cases[i].Runs = append(out.Cases[i].Runs, jsonResult{run})
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// resultText returns the details of a result as plain text
+func resultText(r *pb.Result) string {
+	if r.Details != "" {
+		return r.Details
+	}
+	return r.StructuredDetails
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      float64         `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+	SystemOut string          `xml:"system-out,omitempty"`
+	SystemErr string          `xml:"system-err,omitempty"`
+}
+
+func writeJUnit(w io.Writer, r *Report) error {
+	suite := junitTestSuite{
+		Name:      r.Homework,
+		SystemOut: r.Scoreboard,
+		SystemErr: r.SubmitError,
+	}
+	build := junitTestCase{Name: "build", ClassName: r.Homework}
+	switch r.Build {
+	case BuildFailed:
+		build.Failure = &junitMessage{Message: "build failed"}
+		suite.Failures++
+	case BuildSkipped:
+		build.Skipped = &junitMessage{Message: "build skipped"}
+		suite.Skipped++
+	}
+	suite.TestCases = append(suite.TestCases, build)
+	for _, c := range r.Cases {
+		tc := junitTestCase{Name: c.Case, ClassName: r.Homework}
+		switch {
+		case c.Result == nil:
+			tc.Skipped = &junitMessage{Message: "not judged"}
+			suite.Skipped++
+		case c.Result.Verdict == verdictInternalError:
+			tc.Error = &junitMessage{Message: c.Result.Verdict, Text: resultText(c.Result)}
+			suite.Errors++
+		case !c.Result.Passed:
+			tc.Failure = &junitMessage{Message: c.Result.Verdict, Text: resultText(c.Result)}
+			suite.Failures++
+		default:
+			tc.SystemOut = resultText(c.Result)
+		}
+		if c.Result != nil {
+			tc.Time = c.Result.Time
+			suite.Time += c.Result.Time
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	suite.Tests = len(suite.TestCases)
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(struct {
+		XMLName xml.Name `xml:"testsuites"`
+		Suite   junitTestSuite
+	}{Suite: suite})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// tapEscape makes s safe to put in a TAP description
+func tapEscape(s string) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, "#", "\\#", -1)
+	return strings.Replace(s, "\n", " ", -1)
+}
+
+func writeTAP(w io.Writer, r *Report) error {
+	var b strings.Builder
+	fmt.Fprintln(&b, "TAP version 13")
+	fmt.Fprintf(&b, "1..%d\n", len(r.Cases)+1)
+	switch r.Build {
+	case BuildOK:
+		fmt.Fprintln(&b, "ok 1 - build")
+	case BuildCached:
+		fmt.Fprintln(&b, "ok 1 - build # using the cached executable")
+	case BuildSkipped:
+		fmt.Fprintln(&b, "ok 1 - build # SKIP using a prebuilt executable")
+	default:
+		fmt.Fprintln(&b, "not ok 1 - build")
+	}
+	for i, c := range r.Cases {
+		n := i + 2
+		if c.Result == nil {
+			fmt.Fprintf(&b, "ok %d - %s # SKIP not judged\n", n, tapEscape(c.Case))
+			continue
+		}
+		status := "ok"
+		if !c.Result.Passed {
+			status = "not ok"
+		}
+		fmt.Fprintf(&b, "%s %d - %s\n", status, n, tapEscape(c.Case))
+		fmt.Fprintln(&b, "  ---")
+		fmt.Fprintf(&b, "  verdict: %q\n", c.Result.Verdict)
+		fmt.Fprintf(&b, "  time: %g\n", c.Result.Time)
+		if details := resultText(c.Result); details != "" {
+			fmt.Fprintf(&b, "  details: %q\n", details)
+		}
+		if len(c.Runs) > 0 {
+			fmt.Fprintln(&b, "  runs:")
+			for _, run := range c.Runs {
+				fmt.Fprintf(&b, "    - {passed: %t, time: %g, verdict: %q}\n", run.Passed, run.Time, run.Verdict)
+			}
+		}
+		fmt.Fprintln(&b, "  ...")
+	}
+	if r.Scoreboard != "" {
+		fmt.Fprintf(&b, "# scoreboard: %s\n", tapEscape(r.Scoreboard))
+	}
+	if r.SubmissionID != "" {
+		fmt.Fprintf(&b, "# submission: %s\n", r.SubmissionID)
+	}
+	if r.SubmitError != "" {
+		fmt.Fprintf(&b, "# submit error: %s\n", tapEscape(r.SubmitError))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,85 @@
+package judge
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testReport() *Report {
+	return &Report{
+		Homework: "hw1",
+		User:     "ipc21s01",
+		Build:    BuildOK,
+		Cases: []CaseReport{
+			{Case: "01", Result: &pb.Result{Case: "01", Passed: true, Time: 1.5, Verdict: "accepted"}},
+			{Case: "02", Result: &pb.Result{Case: "02", Time: 2, Verdict: "wrong answer", Details: "line #3"}},
+			{Case: "03"},
+		},
+		Submitted:  true,
+		Scoreboard: "created {1 1.50}",
+	}
+}
+
+func TestWriteTAP(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, WriteReport(&b, FormatTAP, testReport()))
+	assert.Equal(t, `TAP version 13
+1..4
+ok 1 - build
+ok 2 - 01
+  ---
+  verdict: "accepted"
+  time: 1.5
+  ...
+not ok 3 - 02
+  ---
+  verdict: "wrong answer"
+  time: 2
+  details: "line #3"
+  ...
+ok 4 - 03 # SKIP not judged
+# scoreboard: created {1 1.50}
+`, b.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, WriteReport(&b, FormatJSON, testReport()))
+	var out struct {
+		Build string
+		Cases []struct {
+			Case   string
+			Result *struct {
+				Passed  bool
+				Verdict string
+			}
+		}
+	}
+	assert.NoError(t, json.Unmarshal(b.Bytes(), &out))
+	assert.Equal(t, BuildOK, out.Build)
+	assert.Len(t, out.Cases, 3)
+	assert.Equal(t, "wrong answer", out.Cases[1].Result.Verdict)
+	assert.Nil(t, out.Cases[2].Result)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, WriteReport(&b, FormatJUnit, testReport()))
+	var out struct {
+		Suite junitTestSuite `xml:"testsuite"`
+	}
+	assert.NoError(t, xml.Unmarshal(b.Bytes(), &out))
+	assert.Equal(t, 4, out.Suite.Tests)
+	assert.Equal(t, 1, out.Suite.Failures)
+	assert.Equal(t, 1, out.Suite.Skipped)
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	assert.Error(t, WriteReport(&bytes.Buffer{}, "yaml", testReport()))
+}
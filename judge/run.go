@@ -0,0 +1,512 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NTHU-lsalab/sb"
+	"github.com/NTHU-lsalab/sb/client"
+	"github.com/NTHU-lsalab/sb/intrange"
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// previousTimes collects the times of the cases from the cached previous runs,
+// the submission stored in the scoreboard and the reference times of the homework,
+// in order of preference
+func previousTimes(ctx context.Context, logger *log.Logger, c pb.ScoreboardClient, hw *pb.Homework, user string) map[string]float64 {
+	times, err := loadCachedTimes(hw.Name)
+	if err != nil {
+		logger.Printf("Failed to load the cached times of the cases: %v", err)
+		times = make(map[string]float64)
+	}
+	if c != nil {
+		ctx, cancel := context.WithTimeout(ctx, time.Second*3)
+		defer cancel()
+		stored, err := c.QueryCaseTimes(ctx, &pb.QueryCaseTimesRequest{
+			Homework: hw.Name,
+			User:     user,
+		})
+		if err == nil {
+			for casename, t := range stored.Times {
+				if _, ok := times[casename]; !ok {
+					times[casename] = t
+				}
+			}
+		}
+	}
+	for casename, t := range hw.ReferenceTimes {
+		if _, ok := times[casename]; !ok {
+			times[casename] = t
+		}
+	}
+	return times
+}
+
+// Options are the options of a judge run, as given on the command line of xjudge
+type Options struct {
+	Chdir          string        // judge the sources in this directory instead of the working directory
+	ExcludeCases   []string      // exclude these cases
+	IncludeCases   []string      // include these cases
+	CasesFrom      string        // include the cases listed in this file
+	AsUser         string        // run the judge as this user. privileged.
+	RuleFile       string        // use the rules defined in the config file instead of argv[0]. privileged.
+	Server         string        // the judge server
+	Homework       string        // the name of the homework
+	Bin            string        // skip compiling and use the given binary. privileged.
+	MedianOf       int           // run each case multiple times and pick the median as the result
+	Debug          bool          // output debug messages
+	Cgroup         bool          // account the resource usage of runners with cgroup v2
+	Format         string        // format of the report, one of the Format* constants
+	Output         string        // write the report to this file instead of stdout
+	NoSubmit       bool          // judge locally without submitting the results
+	Offline        bool          // use the cached homework definition without connecting to the scoreboard. implies NoSubmit.
+	Resume         bool          // only judge the cases missing from the interrupted session of the same sources
+	SubmitPartial  bool          // submit the results even if not all cases were judged
+	BuildCacheSize int64         // maximum size of the build cache in MiB, 0 disables the cache
+	Timing         bool          // run each case adaptively until the timing is stable
+	Warmup         int           // number of warmup runs of each case in timing mode
+	MinRuns        int           // minimum number of measured runs in timing mode
+	MaxRuns        int           // maximum number of measured runs in timing mode
+	TargetCI       float64       // target relative half-width of the 95% confidence interval in timing mode
+	ConfigOrder    bool          // run the cases in config order instead of longest first
+	FailFast       bool          // stop judging after the first failing case
+	Retries        int           // retry runs with internal errors up to this many times
+	RetryBackoff   time.Duration // delay before the first retry, doubled for each further retry
+}
+
+// Config configures Run. The dependencies left unset default to those used by xjudge.
+//
+// The resource limits of a homework are applied by running the runners through
+// a helper, which re-executes /proc/self/exe. Programs embedding the judge must
+// call RunSandboxHelper at the start of main, otherwise the program itself is
+// started again in place of the runners of homeworks with limits.
+type Config struct {
+	Options
+
+	User    string              // the user running the judge, defaults to the current user
+	Logger  *log.Logger         // progress messages, defaults to stderr
+	Stdout  io.Writer           // the report if Output is not set, defaults to os.Stdout
+	Stderr  io.Writer           // output of the compiler and of the runners in debug mode, defaults to os.Stderr
+	Client  pb.ScoreboardClient // the scoreboard, dialed at Server if nil
+	TempDir string              // parent of the temporary build directory, defaults to the home directory
+}
+
+// setDefaults fills in the unset dependencies of the config
+func (config *Config) setDefaults() error {
+	if config.User == "" || config.TempDir == "" {
+		currentUser, err := user.Current()
+		if err != nil {
+			return fmt.Errorf("cannot get user: %v", err)
+		}
+		if config.User == "" {
+			config.User = currentUser.Username
+		}
+		if config.TempDir == "" {
+			config.TempDir = currentUser.HomeDir
+		}
+	}
+	if config.AsUser == "" {
+		config.AsUser = config.User
+	}
+	if config.Logger == nil {
+		config.Logger = log.New(os.Stderr, "", 0)
+	}
+	if config.Stdout == nil {
+		config.Stdout = os.Stdout
+	}
+	if config.Stderr == nil {
+		config.Stderr = os.Stderr
+	}
+	if config.MedianOf == 0 {
+		config.MedianOf = 1
+	}
+	return nil
+}
+
+// writeReportFile writes the report as specified in the config
+func writeReportFile(config *Config, report *Report) error {
+	if config.Format == FormatText || config.Format == "" {
+		return nil
+	}
+	w := config.Stdout
+	if config.Output != "" {
+		var f *os.File
+		err := asUser(func() (err error) {
+			f, err = os.Create(config.Output)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create report: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	err := WriteReport(w, config.Format, report)
+	if err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+	return nil
+}
+
+// loadRuleFile loads the homework from a config file
+func loadRuleFile(filename string) (hw *pb.Homework, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("bad rule file: %v", r)
+		}
+	}()
+	return sb.LoadHomework(filename), nil
+}
+
+// Run judges the homework in Chdir or the current directory as configured, and submits
+// the results to the scoreboard unless NoSubmit is set. The report is returned
+// once the cases are judged, even if writing it or submitting the results failed.
+// Cancelling ctx stops judging, and the cases judged so far are saved in a session.
+func Run(ctx context.Context, config Config) (*Report, error) {
+	err := config.setDefaults()
+	if err != nil {
+		return nil, err
+	}
+	logger := config.Logger
+
+	switch config.Format {
+	case "", FormatText, FormatJSON, FormatJUnit, FormatTAP:
+	default:
+		return nil, fmt.Errorf("unknown output format %q", config.Format)
+	}
+	var casesFrom *casesFile
+	if config.CasesFrom != "" {
+		casesFrom, err = readCasesFile(config.CasesFrom)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cases from %s: %v", config.CasesFrom, err)
+		}
+	}
+	// the sources, the rule file and the binary are named relative to Chdir,
+	// which is not made the working directory of the process
+	var dir string
+	if config.Chdir != "" {
+		fi, err := os.Stat(config.Chdir)
+		if err == nil && !fi.IsDir() {
+			err = fmt.Errorf("%s is not a directory", config.Chdir)
+		}
+		if err == nil {
+			dir, err = filepath.Abs(config.Chdir)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bad directory: %v", err)
+		}
+	}
+	if config.Offline {
+		config.NoSubmit = true
+	}
+	c := config.Client
+	var conn *client.Client
+	if c == nil && !config.Offline {
+		conn, err = client.Dial(ctx, client.Options{Address: config.Server, Retries: 2, Version: sb.Version})
+		if err == nil {
+			defer conn.Close()
+			c = conn.Scoreboard()
+		} else if config.NoSubmit {
+			logger.Printf("Using the cached homework: %v", err)
+		} else {
+			return nil, err
+		}
+	}
+
+	var hw *pb.Homework
+	if config.RuleFile != "" {
+		if !sb.Privileged() {
+			return nil, errors.New("cannot specify rule file when not privileged")
+		}
+		hw, err = loadRuleFile(inDir(dir, config.RuleFile))
+		if err != nil {
+			return nil, err
+		}
+	} else if c != nil {
+		hw, err = c.QueryHomework(ctx, &pb.QueryHomeworkRequest{
+			Name: config.Homework,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get homework %s: %v", config.Homework, err)
+		}
+		if config.Debug && hw.ServerVersion != "" {
+			logger.Printf("Scoreboard version %s", hw.ServerVersion)
+		}
+		if conn != nil {
+			if _, protocol := conn.ServerVersion(); protocol > sb.ProtocolVersion {
+				logger.Printf("The scoreboard speaks protocol version %d, newer than version %d of this judge. Consider upgrading xjudge.", protocol, sb.ProtocolVersion)
+			}
+		}
+		hw.ServerVersion = ""
+		err = saveCachedHomework(hw)
+		if err != nil {
+			logger.Printf("Failed to cache homework %s: %v", hw.Name, err)
+		}
+	} else {
+		// the cache is writable by the user, so its runner and fallback files
+		// must not be used with the setgid privilege of the judge
+		err = dropPrivilege()
+		if err != nil {
+			return nil, fmt.Errorf("failed to drop privileges: %v", err)
+		}
+		hw, err = loadCachedHomework(config.Homework)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cached homework %s: %v", config.Homework, err)
+		}
+	}
+
+	if config.AsUser != config.User && !sb.Privileged() {
+		return nil, errors.New("cannot run as other user when not privileged")
+	}
+
+	include := append([]string(nil), config.IncludeCases...)
+	if casesFrom != nil {
+		err = casesFrom.check(hw.Cases)
+		if err != nil {
+			return nil, err
+		}
+		include = append(include, casesFrom.patterns...)
+	}
+	cases, excluded, err := filterCases(hw.Cases, include, config.ExcludeCases)
+	if err != nil {
+		return nil, err
+	}
+	if len(excluded) > 0 {
+		logger.Println("Excluded", strings.Join(intrange.Compress(excluded), " "))
+	}
+	if len(cases) == 0 {
+		return nil, errors.New("no cases to judge")
+	}
+
+	if config.MedianOf%2 == 0 {
+		return nil, errors.New("refusing to pick a median from a even number of runs")
+	}
+	var timing *Timing
+	if config.Timing {
+		if config.MedianOf != 1 {
+			return nil, errors.New("cannot use --median-of in timing mode")
+		}
+		timing = &Timing{
+			Warmup:   config.Warmup,
+			MinRuns:  config.MinRuns,
+			MaxRuns:  config.MaxRuns,
+			TargetCI: config.TargetCI,
+		}
+		err = timing.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("bad timing options: %v", err)
+		}
+	}
+
+	rule := Rule{
+		Dir:      dir,
+		Target:   hw.Target,
+		Runner:   hw.Runner,
+		Optional: make([]OptionalFile, len(hw.Files)),
+		MedianOf: config.MedianOf,
+		Debug:    config.Debug,
+		Limits:   hw.Limits,
+		Timing:   timing,
+
+		TimingPolicy:  hw.TimingPolicy,
+		PreviousTimes: previousTimes(ctx, logger, c, hw, config.AsUser),
+		ConfigOrder:   config.ConfigOrder,
+		FailFast:      config.FailFast,
+		Retries:       config.Retries,
+		RetryBackoff:  config.RetryBackoff,
+
+		Log:     logger,
+		Stderr:  config.Stderr,
+		TempDir: inDir(dir, config.TempDir),
+	}
+
+	if rule.Limits.GetSandbox() && !sandboxAvailable() {
+		logger.Println("Sandbox is not available on this machine, running without it")
+		rule.Limits = proto.Clone(rule.Limits).(*pb.Limits)
+		rule.Limits.Sandbox = false
+	}
+
+	// the caches are writable by the user, so only the sessions and executables
+	// signed with the key readable by the judge may be used for submitting
+	key, keyErr := sessionKey()
+	if keyErr != nil {
+		key = nil
+	}
+
+	if config.BuildCacheSize > 0 && keyErr != nil && !config.NoSubmit {
+		logger.Printf("Cannot verify the build cache, not using it: %v", keyErr)
+	} else if config.BuildCacheSize > 0 {
+		rule.BuildCache, err = newBuildCache(config.BuildCacheSize*1024*1024, key)
+		if err != nil {
+			logger.Printf("Cannot use the build cache: %v", err)
+		}
+	}
+
+	if config.Cgroup {
+		rule.Cgroup, err = setupCgroup()
+		if err != nil {
+			logger.Printf("Cannot use cgroup for resource accounting: %v", err)
+		}
+	}
+
+	for i, source := range hw.Files {
+		rule.Optional[i].Name = source.Name
+		rule.Optional[i].Fallback = source.Fallback
+	}
+
+	if config.Bin != "" {
+		if sb.Privileged() {
+			rule.SkipCompile = true
+			rule.Target = config.Bin
+		} else {
+			logger.Println("Cannot skip compiling when not privileged")
+		}
+	}
+
+	sess := &session{
+		Homework: hw.Name,
+		User:     config.AsUser,
+		MedianOf: rule.MedianOf,
+	}
+	sess.SourceHash, err = sourceHash(rule)
+	if err != nil {
+		logger.Printf("Cannot hash the source files, an interrupted run will not be resumable: %v", err)
+	}
+	if config.Resume && sess.SourceHash != "" && keyErr != nil && !config.NoSubmit {
+		logger.Printf("Cannot verify the interrupted session, judging all cases. Use --no-submit to resume it without submitting: %v", keyErr)
+	} else if config.Resume && sess.SourceHash != "" {
+		saved, err := loadSession(hw.Name, config.AsUser, sess.SourceHash, key)
+		if err != nil {
+			logger.Printf("Failed to load session: %v", err)
+		} else if saved == nil {
+			logger.Println("No interrupted session of the current source files, judging all cases")
+		} else if saved.MedianOf != rule.MedianOf {
+			logger.Printf("The interrupted session used --median-of=%d, judging all cases", saved.MedianOf)
+		} else {
+			sess = saved
+		}
+	}
+	judged := make(map[string]*pb.Result)
+	for _, r := range sess.Results {
+		judged[r.Case] = r
+	}
+	pending := make([]string, 0, len(cases))
+	for _, kase := range cases {
+		if _, ok := judged[kase]; !ok {
+			pending = append(pending, kase)
+		}
+	}
+	if len(pending) < len(cases) {
+		logger.Printf("Resuming session: %d of %d cases already judged", len(cases)-len(pending), len(cases))
+	}
+
+	report := &Report{
+		Homework: hw.Name,
+		User:     config.AsUser,
+		Build:    BuildSkipped,
+	}
+	if len(pending) > 0 {
+		judge(ctx, rule, pending, report)
+	}
+	for _, c := range report.Cases {
+		if c.Result != nil {
+			judged[c.Case] = c.Result
+		}
+	}
+	runs := make(map[string][]*pb.Result)
+	for _, c := range report.Cases {
+		runs[c.Case] = c.Runs
+	}
+	report.Cases = make([]CaseReport, len(cases))
+	missing := 0
+	for i, kase := range cases {
+		report.Cases[i] = CaseReport{Case: kase, Result: judged[kase], Runs: runs[kase]}
+		if judged[kase] == nil {
+			missing++
+		}
+	}
+	if report.Build == BuildFailed {
+		return report, writeReportFile(&config, report)
+	}
+	result := report.Results()
+	if len(result) == 0 {
+		return report, writeReportFile(&config, report)
+	}
+	err = saveCachedTimes(hw.Name, result)
+	if err != nil {
+		logger.Printf("Failed to cache the times of the cases: %v", err)
+	}
+	if sess.SourceHash != "" {
+		// failed cases are judged again on resume, e.g. after a fix of a flaky
+		// runner or when --fail-fast stopped at them
+		sess.Results = nil
+		for _, r := range result {
+			if r.Passed {
+				sess.Results = append(sess.Results, r)
+			}
+		}
+		if missing > 0 {
+			err = sess.save(key)
+			if err != nil {
+				logger.Printf("Failed to save session: %v", err)
+			} else {
+				logger.Printf("Saved the session, run with --resume to judge the remaining %d cases", missing)
+			}
+		} else {
+			err = sess.remove()
+			if err != nil {
+				logger.Printf("Failed to remove session: %v", err)
+			}
+		}
+	}
+	if missing > 0 && !config.SubmitPartial && !config.NoSubmit {
+		logger.Printf("Not submitting incomplete results, %d cases were not judged. Use --submit-partial to submit anyway", missing)
+		return report, writeReportFile(&config, report)
+	}
+	if config.NoSubmit {
+		logger.Println("Not submitting the results to the scoreboard")
+		return report, writeReportFile(&config, report)
+	}
+
+	// submit even if ctx was cancelled after judging some cases with SubmitPartial
+	sbCtx, sbCancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer sbCancel()
+	r, err := c.Submit(sbCtx, &pb.UserSubmission{
+		User:     config.AsUser,
+		Homework: hw.Name,
+		Results:  result,
+		CodeHash: sess.SourceHash,
+	})
+	if err != nil {
+		report.SubmitError = err.Error()
+		writeReportFile(&config, report)
+		return report, fmt.Errorf("failed to submit results to scoreboard: %v", err)
+	}
+	report.Submitted = true
+	report.Scoreboard = r.Message
+	logger.Println("Scoreboard:", r.Message)
+	if r.Record != nil {
+		report.SubmissionID = r.Record.Id
+		logger.Printf("Submission %s at %s", r.Record.Id,
+			time.Unix(0, r.Record.TimeUnixNano).Format("2006-01-02 15:04:05"))
+	}
+	if r.Receipt != nil {
+		filename, err := saveReceipt(r)
+		if err != nil {
+			logger.Printf("Failed to save the receipt: %v", err)
+		} else {
+			logger.Printf("Receipt signed by key %s, saved to %s", r.Receipt.KeyId, filename)
+		}
+	}
+	return report, writeReportFile(&config, report)
+}
@@ -0,0 +1,252 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeScoreboard serves a single homework and records the submissions
+type fakeScoreboard struct {
+	homework    *pb.Homework
+	submissions []*pb.UserSubmission
+}
+
+func (f *fakeScoreboard) Submit(ctx context.Context, in *pb.UserSubmission, opts ...grpc.CallOption) (*pb.SubmissionReply, error) {
+	f.submissions = append(f.submissions, in)
+	return &pb.SubmissionReply{Message: "accepted"}, nil
+}
+
+func (f *fakeScoreboard) QueryHomework(ctx context.Context, in *pb.QueryHomeworkRequest, opts ...grpc.CallOption) (*pb.Homework, error) {
+	return f.homework, nil
+}
+
+func (f *fakeScoreboard) QueryCaseTimes(ctx context.Context, in *pb.QueryCaseTimesRequest, opts ...grpc.CallOption) (*pb.CaseTimes, error) {
+	return &pb.CaseTimes{}, nil
+}
+
+func (f *fakeScoreboard) WatchBoard(ctx context.Context, in *pb.WatchBoardRequest, opts ...grpc.CallOption) (pb.Scoreboard_WatchBoardClient, error) {
+	return nil, errors.New("not implemented")
+}
+
+func writeFile(t *testing.T, filename, content string, perm os.FileMode) {
+	require.NoError(t, ioutil.WriteFile(filename, []byte(content), perm))
+}
+
+// setupRun creates a temporary directory with a cache and the sources of a
+// homework in work, restoring the environment when the test ends
+func setupRun(t *testing.T) (dir, work string) {
+	dir, err := ioutil.TempDir("", "judge-run-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	os.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Cleanup(func() { os.Setenv("XDG_CACHE_HOME", cacheHome) })
+
+	work = filepath.Join(dir, "work")
+	require.NoError(t, os.Mkdir(work, 0755))
+	writeFile(t, filepath.Join(work, "Makefile"), "hw:\n\tprintf '#!/bin/sh\\n' > hw && chmod +x hw\n", 0644)
+	return dir, work
+}
+
+func TestRun(t *testing.T) {
+	dir, work := setupRun(t)
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	// the runner starts in the directory of the sources
+	runner := filepath.Join(dir, "runner")
+	writeFile(t, runner, `#!/bin/sh
+if [ "$1" = "02" ] || [ ! -f Makefile ]; then
+	echo '{"passed":false,"time":2,"verdict":"wrong answer"}'
+else
+	echo '{"passed":true,"time":1,"verdict":"accepted"}'
+fi
+`, 0755)
+
+	scoreboard := &fakeScoreboard{homework: &pb.Homework{
+		Name:   "hw",
+		Target: "hw",
+		Runner: runner,
+		Files:  []*pb.SourceFile{{Name: "Makefile"}},
+		Cases:  []string{"01", "02", "03"},
+	}}
+	var logs, stdout bytes.Buffer
+	report, err := Run(context.Background(), Config{
+		Options: Options{
+			Chdir:        work,
+			Homework:     "hw",
+			ExcludeCases: []string{"03"},
+			Format:       FormatTAP,
+		},
+		User:    "student",
+		Logger:  log.New(&logs, "", 0),
+		Stdout:  &stdout,
+		Stderr:  ioutil.Discard,
+		Client:  scoreboard,
+		TempDir: dir,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, BuildOK, report.Build)
+	assert.True(t, report.Submitted)
+	assert.Equal(t, "accepted", report.Scoreboard)
+	require.Len(t, report.Cases, 2)
+	assert.True(t, report.Cases[0].Result.Passed)
+	assert.False(t, report.Cases[1].Result.Passed)
+
+	require.Len(t, scoreboard.submissions, 1)
+	assert.Equal(t, "student", scoreboard.submissions[0].User)
+	assert.Len(t, scoreboard.submissions[0].Results, 2)
+
+	assert.Contains(t, logs.String(), "Excluded 03")
+	assert.Contains(t, stdout.String(), "not ok 3 - 02")
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, cwd, wd, "Run must not change the working directory")
+
+	// offline runs use the cached definition
+	report, err = Run(context.Background(), Config{
+		Options: Options{
+			Chdir:    work,
+			Homework: "hw",
+			Offline:  true,
+		},
+		User:    "student",
+		Logger:  log.New(ioutil.Discard, "", 0),
+		Stdout:  ioutil.Discard,
+		Stderr:  ioutil.Discard,
+		TempDir: dir,
+	})
+	require.NoError(t, err)
+	assert.False(t, report.Submitted)
+	assert.Len(t, report.Cases, 3)
+}
+
+func TestRunRetries(t *testing.T) {
+	dir, work := setupRun(t)
+	runner := filepath.Join(dir, "runner")
+	// case 01 fails to run once, case 02 always
+	writeFile(t, runner, `#!/bin/sh
+if [ "$1" = "02" ] || [ ! -e "$0.$1" ]; then
+	touch "$0.$1"
+	echo garbage
+else
+	echo '{"passed":true,"time":1,"verdict":"accepted"}'
+fi
+`, 0755)
+
+	scoreboard := &fakeScoreboard{homework: &pb.Homework{
+		Name:   "hw",
+		Target: "hw",
+		Runner: runner,
+		Files:  []*pb.SourceFile{{Name: "Makefile"}},
+		Cases:  []string{"01", "02"},
+	}}
+	var logs bytes.Buffer
+	report, err := Run(context.Background(), Config{
+		Options: Options{
+			Chdir:        work,
+			Homework:     "hw",
+			Retries:      2,
+			RetryBackoff: time.Millisecond,
+			NoSubmit:     true,
+		},
+		User:    "student",
+		Logger:  log.New(&logs, "", 0),
+		Stdout:  ioutil.Discard,
+		Stderr:  ioutil.Discard,
+		Client:  scoreboard,
+		TempDir: dir,
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Cases, 2)
+
+	assert.True(t, report.Cases[0].Result.Passed)
+	assert.Regexp(t, `^retried once after an internal error: runner output invalid`, report.Cases[0].Result.Details)
+	assert.False(t, report.Cases[1].Result.Passed)
+	assert.Equal(t, "internal error", report.Cases[1].Result.Verdict)
+	assert.Regexp(t, `\nretried 2 times after internal errors: runner output invalid: .*; runner output invalid`, report.Cases[1].Result.Details)
+	assert.Contains(t, logs.String(), "retrying in 1ms (1/2)")
+	assert.Contains(t, logs.String(), "retrying in 2ms (2/2)")
+}
+
+func TestRunFailFast(t *testing.T) {
+	dir, work := setupRun(t)
+	runner := filepath.Join(dir, "runner")
+	// case 02 fails while the later cases are still running
+	writeFile(t, runner, `#!/bin/sh
+case "$1" in
+01) echo '{"passed":true,"time":1,"verdict":"accepted"}' ;;
+02) sleep 0.2; echo '{"passed":false,"time":1,"verdict":"wrong answer"}' ;;
+*) sleep 10; echo '{"passed":true,"time":1,"verdict":"accepted"}' ;;
+esac
+`, 0755)
+
+	scoreboard := &fakeScoreboard{homework: &pb.Homework{
+		Name:   "hw",
+		Target: "hw",
+		Runner: runner,
+		Files:  []*pb.SourceFile{{Name: "Makefile"}},
+		Cases:  []string{"01", "02", "03", "04", "05"},
+	}}
+	config := Config{
+		Options: Options{
+			Chdir:       work,
+			Homework:    "hw",
+			ConfigOrder: true,
+			FailFast:    true,
+		},
+		User:    "student",
+		Logger:  log.New(ioutil.Discard, "", 0),
+		Stdout:  ioutil.Discard,
+		Stderr:  ioutil.Discard,
+		Client:  scoreboard,
+		TempDir: dir,
+	}
+	start := time.Now()
+	report, err := Run(context.Background(), config)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start).Seconds(), 5.0)
+	assert.False(t, report.Submitted)
+	assert.Empty(t, scoreboard.submissions)
+	assert.True(t, report.Cases[0].Result.Passed)
+	assert.False(t, report.Cases[1].Result.Passed)
+	for _, c := range report.Cases[2:] {
+		assert.Nil(t, c.Result, c.Case)
+	}
+
+	// the failing case is not part of the saved session
+	writeFile(t, runner, `#!/bin/sh
+echo '{"passed":true,"time":1,"verdict":"accepted"}'
+`, 0755)
+	var logs bytes.Buffer
+	config.Logger = log.New(&logs, "", 0)
+	config.FailFast = false
+	config.Resume = true
+	config.NoSubmit = true
+	report, err = Run(context.Background(), config)
+	require.NoError(t, err)
+	assert.Contains(t, logs.String(), "Resuming session: 1 of 5 cases already judged")
+	for _, c := range report.Cases {
+		assert.True(t, c.Result.Passed, c.Case)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	_, err := Run(context.Background(), Config{
+		Options: Options{Format: "yaml"},
+		Logger:  log.New(ioutil.Discard, "", 0),
+	})
+	assert.EqualError(t, err, `unknown output format "yaml"`)
+}
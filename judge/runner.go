@@ -0,0 +1,71 @@
+package judge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// RunnerProtocolVersion is the latest version of the runner output schema
+// understood by the judge
+const RunnerProtocolVersion = 2
+
+// runnerOutput is the JSON object a runner writes to its stdout.
+// Version 1 runners (without the "version" attribute) only output
+// passed, time, verdict and a string details.
+type runnerOutput struct {
+	Version int
+	Passed  bool
+	Time    float64
+	Verdict string
+	Details json.RawMessage
+	Memory  int64   // peak memory usage in KiB
+	CPUTime float64 `json:"cpu_time"`
+	Metrics map[string]float64
+	Node    string
+}
+
+// parseRunnerOutput decodes the output of a runner into result
+func parseRunnerOutput(output []byte, result *judgeResult) error {
+	var ro runnerOutput
+	err := json.Unmarshal(output, &ro)
+	if err != nil {
+		return err
+	}
+	if ro.Version == 0 {
+		ro.Version = 1
+	}
+	if ro.Version > RunnerProtocolVersion {
+		return fmt.Errorf("unsupported runner protocol version %d", ro.Version)
+	}
+	result.Passed = ro.Passed
+	result.Time = ro.Time
+	result.Verdict = ro.Verdict
+
+	details := bytes.TrimSpace(ro.Details)
+	switch {
+	case len(details) == 0 || bytes.Equal(details, []byte("null")):
+	case details[0] == '"':
+		err = json.Unmarshal(details, &result.Details)
+		if err != nil {
+			return err
+		}
+	case ro.Version >= 2 && details[0] == '{':
+		var compact bytes.Buffer
+		err = json.Compact(&compact, details)
+		if err != nil {
+			return err
+		}
+		result.StructuredDetails = compact.String()
+	default:
+		return fmt.Errorf("details must be a string, got %s", details)
+	}
+
+	if ro.Version >= 2 {
+		result.Memory = ro.Memory
+		result.CPUTime = ro.CPUTime
+		result.Metrics = ro.Metrics
+		result.Node = ro.Node
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package judge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRunnerOutputV1(t *testing.T) {
+	var r judgeResult
+	assert.NoError(t, parseRunnerOutput([]byte(`{"passed": true, "time": 1.5, "verdict": "accepted", "details": null}`), &r))
+	assert.Equal(t, judgeResult{Passed: true, Time: 1.5, Verdict: "accepted"}, r)
+}
+
+func TestParseRunnerOutputV1IgnoresV2Fields(t *testing.T) {
+	var r judgeResult
+	assert.NoError(t, parseRunnerOutput([]byte(`{"passed": false, "time": 2, "verdict": "wrong answer", "details": "line 3", "memory": 100}`), &r))
+	assert.Equal(t, judgeResult{Time: 2, Verdict: "wrong answer", Details: "line 3"}, r)
+	assert.Error(t, parseRunnerOutput([]byte(`{"details": {"line": 3}}`), &r))
+}
+
+func TestParseRunnerOutputV2(t *testing.T) {
+	var r judgeResult
+	assert.NoError(t, parseRunnerOutput([]byte(`{
+		"version": 2,
+		"passed": true,
+		"time": 1.5,
+		"verdict": "accepted",
+		"details": {"iterations": [1, 2]},
+		"memory": 2048,
+		"cpu_time": 5.5,
+		"metrics": {"gflops": 12.5},
+		"node": "apollo31"
+	}`), &r))
+	assert.Equal(t, judgeResult{
+		Passed:            true,
+		Time:              1.5,
+		Verdict:           "accepted",
+		StructuredDetails: `{"iterations":[1,2]}`,
+		Memory:            2048,
+		CPUTime:           5.5,
+		Metrics:           map[string]float64{"gflops": 12.5},
+		Node:              "apollo31",
+	}, r)
+}
+
+func TestParseRunnerOutputUnknownVersion(t *testing.T) {
+	var r judgeResult
+	assert.Error(t, parseRunnerOutput([]byte(`{"version": 3}`), &r))
+}
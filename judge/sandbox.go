@@ -0,0 +1,168 @@
+package judge
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/NTHU-lsalab/sb/pb"
+)
+
+// sandboxEnv is set when the judge executes itself as the sandbox helper.
+// It holds the JSON encoded limits to apply to the runner.
+const sandboxEnv = "XJUDGE_SANDBOX_HELPER"
+
+const sandboxCloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWNET
+
+// needsHelper returns whether runners have to be started through the sandbox helper
+func needsHelper(limits *pb.Limits) bool {
+	return limits != nil && (limits.AddressSpace > 0 ||
+		limits.CpuTime > 0 ||
+		limits.Processes > 0 ||
+		limits.OpenFiles > 0 ||
+		limits.FileSize > 0 ||
+		limits.Sandbox)
+}
+
+// helperCommand returns a command that runs the runner through the sandbox helper
+func helperCommand(limits *pb.Limits, name string, args ...string) *exec.Cmd {
+	spec, err := json.Marshal(limits)
+	if err != nil {
+		panic(err)
+	}
+	cmd := exec.Command("/proc/self/exe", append([]string{name}, args...)...)
+	cmd.Env = append(os.Environ(), sandboxEnv+"="+string(spec))
+	return cmd
+}
+
+// sandboxAvailable returns whether unprivileged user namespaces can be created
+func sandboxAvailable() bool {
+	cmd := exec.Command("/bin/true")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:  sandboxCloneflags,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: os.Geteuid(), HostID: os.Geteuid(), Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: os.Getegid(), HostID: os.Getegid(), Size: 1}},
+	}
+	return cmd.Run() == nil
+}
+
+// rlimit is a resource limit of the runner
+type rlimit struct {
+	name     string
+	resource int
+	value    int64
+}
+
+// rlimits returns the rlimits of the limits, leaving out the unlimited ones
+func rlimits(limits *pb.Limits) []rlimit {
+	const mib = 1024 * 1024
+	const rlimitNproc = 6 // RLIMIT_NPROC is missing from package syscall
+	var rl []rlimit
+	for _, l := range []rlimit{
+		{"address space", syscall.RLIMIT_AS, limits.AddressSpace * mib},
+		{"cpu time", syscall.RLIMIT_CPU, limits.CpuTime},
+		{"processes", rlimitNproc, limits.Processes},
+		{"open files", syscall.RLIMIT_NOFILE, limits.OpenFiles},
+		{"file size", syscall.RLIMIT_FSIZE, limits.FileSize * mib},
+	} {
+		if l.value > 0 {
+			rl = append(rl, l)
+		}
+	}
+	return rl
+}
+
+func applyLimits(limits *pb.Limits) error {
+	for _, l := range rlimits(limits) {
+		err := syscall.Setrlimit(l.resource, &syscall.Rlimit{Cur: uint64(l.value), Max: uint64(l.value)})
+		if err != nil {
+			return fmt.Errorf("failed to limit %s: %v", l.name, err)
+		}
+	}
+	return nil
+}
+
+// dropPrivilege drops the setgid privilege of the judge for the rest of the process
+func dropPrivilege() error {
+	gid := os.Getgid()
+	return syscall.Setresgid(gid, gid, gid)
+}
+
+// asUser runs fn with the effective gid set to the real gid, so that the files
+// it opens or creates at paths chosen by the user are accessed with the
+// permissions of the user rather than those of the setgid group of the judge.
+// The privilege is restored afterwards. As it changes the credentials of the
+// whole process, it must not be called while cases are judged.
+func asUser(fn func() error) error {
+	egid, gid := os.Getegid(), os.Getgid()
+	if egid == gid {
+		return fn()
+	}
+	err := syscall.Setresgid(-1, gid, -1)
+	if err != nil {
+		return fmt.Errorf("failed to drop privileges: %v", err)
+	}
+	fnErr := fn()
+	err = syscall.Setresgid(-1, egid, -1)
+	if err != nil {
+		return fmt.Errorf("failed to restore privileges: %v", err)
+	}
+	return fnErr
+}
+
+// RunSandboxHelper runs the sandbox helper and exits if the process is started as one.
+// Otherwise it returns immediately. It should be called at the start of main.
+//
+// The helper drops the setgid privilege of the judge, applies the resource limits
+// and runs the runner, optionally in new user, mount and network namespaces.
+func RunSandboxHelper() {
+	spec, ok := os.LookupEnv(sandboxEnv)
+	if !ok {
+		return
+	}
+	os.Unsetenv(sandboxEnv)
+	limits := &pb.Limits{}
+	err := json.Unmarshal([]byte(spec), limits)
+	if err != nil {
+		log.Fatalf("sandbox helper: bad limits: %v", err)
+	}
+	if len(os.Args) < 2 {
+		log.Fatal("sandbox helper: no command given")
+	}
+
+	err = dropPrivilege()
+	if err != nil {
+		log.Fatalf("sandbox helper: failed to drop privileges: %v", err)
+	}
+	err = applyLimits(limits)
+	if err != nil {
+		log.Fatalf("sandbox helper: %v", err)
+	}
+
+	cmd := exec.Command(os.Args[1], os.Args[2:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if limits.Sandbox {
+		uid, gid := os.Getuid(), os.Getgid()
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Cloneflags:  sandboxCloneflags,
+			UidMappings: []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}},
+			GidMappings: []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}},
+		}
+	}
+	err = cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			os.Exit(128 + int(status.Signal()))
+		}
+		os.Exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		log.Fatalf("sandbox helper: %v", err)
+	}
+	os.Exit(0)
+}
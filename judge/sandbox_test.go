@@ -0,0 +1,159 @@
+package judge
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeedsHelper(t *testing.T) {
+	assert.False(t, needsHelper(nil))
+	assert.False(t, needsHelper(&pb.Limits{}))
+	// the memory limit is applied by the judge with cgroup
+	assert.False(t, needsHelper(&pb.Limits{Memory: 512}))
+	for _, limits := range []*pb.Limits{
+		{AddressSpace: 512},
+		{CpuTime: 10},
+		{Processes: 64},
+		{OpenFiles: 128},
+		{FileSize: 16},
+		{Sandbox: true},
+	} {
+		assert.True(t, needsHelper(limits), "%v", limits)
+	}
+}
+
+func TestRlimits(t *testing.T) {
+	assert.Empty(t, rlimits(&pb.Limits{Memory: 512, Sandbox: true}))
+	assert.Equal(t, []rlimit{
+		{"address space", syscall.RLIMIT_AS, 512 << 20},
+		{"cpu time", syscall.RLIMIT_CPU, 10},
+		{"processes", 6, 64},
+		{"open files", syscall.RLIMIT_NOFILE, 128},
+		{"file size", syscall.RLIMIT_FSIZE, 16 << 20},
+	}, rlimits(&pb.Limits{AddressSpace: 512, CpuTime: 10, Processes: 64, OpenFiles: 128, FileSize: 16}))
+	assert.Equal(t, []rlimit{{"cpu time", syscall.RLIMIT_CPU, 10}}, rlimits(&pb.Limits{CpuTime: 10}))
+}
+
+func TestJudgeCaseMemoryLimit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "judge-case-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	runner := filepath.Join(dir, "runner")
+	writeFile(t, runner, `#!/bin/sh
+echo '{"version":2,"passed":true,"time":1,"verdict":"accepted","details":"12 iterations","memory":4096}'
+`, 0755)
+
+	result := judgeCase(context.Background(), judgeRequest{
+		CaseName: "01",
+		Runner:   runner,
+		Limits:   &pb.Limits{Memory: 2},
+		Log:      log.New(ioutil.Discard, "", 0),
+	})
+	assert.False(t, result.Passed)
+	assert.Equal(t, "memory limit exceeded", result.Verdict)
+	// the peak memory also counts the shell running the runner
+	assert.Regexp(t, `^12 iterations\npeak memory \d+\.\dM, limit 2\.0M$`, result.Details)
+}
+
+// setgidGroup is the group of the setgid copy of the test binary, standing in
+// for the scoreboardd group of xjudge
+const setgidGroup = 1000
+
+// setgidTestEnv is set to the directory of the test in the setgid copy
+const setgidTestEnv = "JUDGE_SETGID_TEST_DIR"
+
+// nobody is the user running the setgid copy of the test binary
+const nobody = 65534
+
+// setgidChild returns the directory of the test, and whether the test runs in
+// the setgid copy of the test binary started by runSetgid
+func setgidChild() (string, bool) {
+	return os.LookupEnv(setgidTestEnv)
+}
+
+// setgidDir creates a directory for a setgid test, which is skipped unless
+// the tests run as root
+func setgidDir(t *testing.T) string {
+	if os.Geteuid() != 0 {
+		t.Skip("setgid tests need root")
+	}
+	dir, err := ioutil.TempDir("", "judge-setgid-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	require.NoError(t, os.Chmod(dir, 0755))
+	return dir
+}
+
+// runSetgid runs the test again as nobody, in a copy of the test binary that
+// is setgid setgidGroup, like xjudge run by a student
+func runSetgid(t *testing.T, dir string) {
+	self, err := os.Open(os.Args[0])
+	require.NoError(t, err)
+	defer self.Close()
+	bin := filepath.Join(dir, "judge.test")
+	f, err := os.OpenFile(bin, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0755)
+	require.NoError(t, err)
+	_, err = io.Copy(f, self)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.NoError(t, os.Chown(bin, 0, setgidGroup))
+	require.NoError(t, os.Chmod(bin, 0755|os.ModeSetgid))
+
+	cmd := exec.Command(bin, "-test.run", "^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), setgidTestEnv+"="+dir)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Credential: &syscall.Credential{Uid: nobody, Gid: nobody}}
+	out, err := cmd.CombinedOutput()
+	assert.NoError(t, err, "%s", out)
+	assert.Contains(t, string(out), "--- PASS: "+t.Name())
+}
+
+func TestAsUser(t *testing.T) {
+	if dir, ok := setgidChild(); ok {
+		require.Equal(t, setgidGroup, os.Getegid())
+		groupDir := filepath.Join(dir, "group")
+		userDir := filepath.Join(dir, "user")
+
+		// the setgid group could write to the directory of the group
+		err := asUser(func() error {
+			return ioutil.WriteFile(filepath.Join(groupDir, "report"), nil, 0644)
+		})
+		assert.True(t, os.IsPermission(err), "%v", err)
+		assert.Equal(t, setgidGroup, os.Getegid())
+		config := &Config{Options: Options{Format: FormatJSON, Output: filepath.Join(groupDir, "report.json")}}
+		assert.Error(t, writeReportFile(config, &Report{}))
+
+		// files are created with the group of the user
+		filename := filepath.Join(userDir, "report")
+		require.NoError(t, asUser(func() error {
+			return ioutil.WriteFile(filename, nil, 0644)
+		}))
+		info, err := os.Stat(filename)
+		require.NoError(t, err)
+		assert.EqualValues(t, nobody, info.Sys().(*syscall.Stat_t).Gid)
+		assert.Equal(t, setgidGroup, os.Getegid())
+		return
+	}
+
+	dir := setgidDir(t)
+	groupDir := filepath.Join(dir, "group")
+	require.NoError(t, os.Mkdir(groupDir, 0770))
+	require.NoError(t, os.Chown(groupDir, 0, setgidGroup))
+	require.NoError(t, os.Chmod(groupDir, 0770))
+	userDir := filepath.Join(dir, "user")
+	require.NoError(t, os.Mkdir(userDir, 0755))
+	require.NoError(t, os.Chown(userDir, nobody, nobody))
+	runSetgid(t, dir)
+	_, err := os.Stat(filepath.Join(groupDir, "report.json"))
+	assert.True(t, os.IsNotExist(err))
+}
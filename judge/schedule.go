@@ -0,0 +1,146 @@
+package judge
+
+import (
+	"container/heap"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/NTHU-lsalab/sb/pb"
+)
+
+// numWorkers is the number of cases judged concurrently
+const numWorkers = 4
+
+func cachedTimesFile(homework string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "times", filepath.Base(homework)+".json"), nil
+}
+
+// loadCachedTimes loads the times of the cases in the previous runs of the homework
+func loadCachedTimes(homework string) (times map[string]float64, err error) {
+	err = asUser(func() error {
+		filename, err := cachedTimesFile(homework)
+		if err != nil {
+			return err
+		}
+		times = make(map[string]float64)
+		b, err := ioutil.ReadFile(filename)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(b, &times)
+	})
+	return times, err
+}
+
+// saveCachedTimes updates the cached times of the cases with the results
+func saveCachedTimes(homework string, results []*pb.Result) error {
+	return asUser(func() error {
+		times, err := loadCachedTimes(homework)
+		if err != nil {
+			times = make(map[string]float64)
+		}
+		for _, r := range results {
+			times[r.Case] = r.Time
+		}
+		filename, err := cachedTimesFile(homework)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(filepath.Dir(filename), 0755)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(times)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filename+"-", b, 0644)
+		if err != nil {
+			return err
+		}
+		return os.Rename(filename+"-", filename)
+	})
+}
+
+// estimateTimes returns the estimated time of each case.
+// Cases without a previous time are assumed to be as long as the longest known case.
+// It returns nil if there is no previous time of any case.
+func estimateTimes(cases []string, previous map[string]float64) []float64 {
+	longest := 0.0
+	known := false
+	for _, casename := range cases {
+		if t, ok := previous[casename]; ok {
+			known = true
+			if t > longest {
+				longest = t
+			}
+		}
+	}
+	if !known {
+		return nil
+	}
+	estimates := make([]float64, len(cases))
+	for i, casename := range cases {
+		t, ok := previous[casename]
+		if !ok {
+			t = longest
+		}
+		estimates[i] = t
+	}
+	return estimates
+}
+
+// longestFirst returns the indices of the cases ordered by decreasing estimated time
+func longestFirst(estimates []float64) []int {
+	order := make([]int, len(estimates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return estimates[order[i]] > estimates[order[j]]
+	})
+	return order
+}
+
+type float64Heap []float64
+
+func (h float64Heap) Len() int            { return len(h) }
+func (h float64Heap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h float64Heap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *float64Heap) Push(x interface{}) { *h = append(*h, x.(float64)) }
+func (h *float64Heap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// estimateMakespan returns the time to run the jobs in order on the given number of workers
+func estimateMakespan(jobs []float64, workers int) float64 {
+	loads := make(float64Heap, workers)
+	makespan := 0.0
+	for _, job := range jobs {
+		load := heap.Pop(&loads).(float64) + job
+		if load > makespan {
+			makespan = load
+		}
+		heap.Push(&loads, load)
+	}
+	return makespan
+}
+
+// formatETA formats a duration in seconds for humans
+func formatETA(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second).String()
+}
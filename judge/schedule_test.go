@@ -0,0 +1,24 @@
+package judge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateTimes(t *testing.T) {
+	cases := []string{"a", "b", "c"}
+	assert.Nil(t, estimateTimes(cases, nil))
+	assert.Equal(t, []float64{1, 4, 4}, estimateTimes(cases, map[string]float64{"a": 1, "b": 4}))
+}
+
+func TestLongestFirst(t *testing.T) {
+	assert.Equal(t, []int{1, 3, 0, 2}, longestFirst([]float64{2, 5, 1, 3}))
+	assert.Equal(t, []int{0, 1, 2}, longestFirst([]float64{1, 1, 1}))
+}
+
+func TestEstimateMakespan(t *testing.T) {
+	assert.Equal(t, 0.0, estimateMakespan(nil, 4))
+	assert.Equal(t, 5.0, estimateMakespan([]float64{5, 3, 2}, 2))
+	assert.Equal(t, 6.0, estimateMakespan([]float64{1, 2, 3}, 1))
+}
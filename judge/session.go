@@ -0,0 +1,178 @@
+package judge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/NTHU-lsalab/sb"
+	"github.com/NTHU-lsalab/sb/pb"
+)
+
+// session is the checkpoint of an interrupted judge run
+type session struct {
+	Homework   string
+	User       string
+	SourceHash string
+	MedianOf   int
+	Results    []*pb.Result
+}
+
+// signedSession is the content of a session file
+type signedSession struct {
+	Session   json.RawMessage
+	Signature string // HMAC-SHA256 of Session, empty if there was no key to sign it
+}
+
+// sessionKey reads the key signing the sessions and the build cache
+func sessionKey() ([]byte, error) {
+	return ioutil.ReadFile(sb.SessionKeyFile)
+}
+
+func sign(key, b []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hashFile writes the name and the content of the file to h
+func hashFile(h io.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintf(h, "%s\x00", name)
+	_, err = io.Copy(h, f)
+	return err
+}
+
+// sourceHash returns the hash of the files that would be copied for compiling,
+// or of the executable if compiling is skipped
+func sourceHash(rule Rule) (string, error) {
+	h := sha256.New()
+	if rule.SkipCompile {
+		err := hashFile(h, rule.Target, inDir(rule.Dir, rule.Target))
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	for _, filename := range rule.Mandantory {
+		err := hashFile(h, filename, inDir(rule.Dir, filename))
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, pair := range rule.Optional {
+		err := hashFile(h, pair.Name, inDir(rule.Dir, pair.Name))
+		if os.IsNotExist(err) && pair.Fallback != "" {
+			err = hashFile(h, pair.Name, inDir(rule.Dir, pair.Fallback))
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	fmt.Fprintf(h, "%s\x00%s", rule.Target, rule.Runner)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func sessionFile(homework, user, hash string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s-%s.json", filepath.Base(homework), filepath.Base(user), hash[:16])
+	return filepath.Join(dir, "sessions", name), nil
+}
+
+// loadSession loads the session of the homework, returning nil if there is none.
+// If key is not nil, the session must be signed with it.
+func loadSession(homework, user, hash string, key []byte) (s *session, err error) {
+	err = asUser(func() error {
+		filename, err := sessionFile(homework, user, hash)
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadFile(filename)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		signed := &signedSession{}
+		err = json.Unmarshal(b, signed)
+		if err != nil {
+			return fmt.Errorf("corrupt session %s: %v", filename, err)
+		}
+		if key != nil && !hmac.Equal([]byte(signed.Signature), []byte(sign(key, signed.Session))) {
+			return fmt.Errorf("session %s has a bad signature", filename)
+		}
+		s = &session{}
+		err = json.Unmarshal(signed.Session, s)
+		if err != nil {
+			return fmt.Errorf("corrupt session %s: %v", filename, err)
+		}
+		if s.Homework != homework || s.User != user || s.SourceHash != hash {
+			s = nil
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// save writes the session to the cache so that it can be resumed, signed with key if not nil
+func (s *session) save(key []byte) error {
+	return asUser(func() error {
+		filename, err := sessionFile(s.Homework, s.User, s.SourceHash)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(filepath.Dir(filename), 0755)
+		if err != nil {
+			return err
+		}
+		signed := signedSession{}
+		signed.Session, err = json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		if key != nil {
+			signed.Signature = sign(key, signed.Session)
+		}
+		b, err := json.Marshal(signed)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filename+"-", b, 0644)
+		if err != nil {
+			return err
+		}
+		return os.Rename(filename+"-", filename)
+	})
+}
+
+// remove deletes the session file
+func (s *session) remove() error {
+	return asUser(func() error {
+		filename, err := sessionFile(s.Homework, s.User, s.SourceHash)
+		if err != nil {
+			return err
+		}
+		err = os.Remove(filename)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	})
+}
@@ -0,0 +1,58 @@
+package judge
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionSignature(t *testing.T) {
+	dir, err := ioutil.TempDir("", "judge-session-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	os.Setenv("XDG_CACHE_HOME", dir)
+	defer os.Setenv("XDG_CACHE_HOME", cacheHome)
+
+	key := []byte("secret")
+	hash := "0123456789abcdef0123456789abcdef"
+	sess := &session{Homework: "hw", User: "student", SourceHash: hash, MedianOf: 1, Results: []*pb.Result{
+		{Case: "01", Passed: true, Time: 1.5, Verdict: "accepted"},
+	}}
+	require.NoError(t, sess.save(key))
+
+	loaded, err := loadSession("hw", "student", hash, key)
+	require.NoError(t, err)
+	require.NotNil(t, loaded)
+	require.Len(t, loaded.Results, 1)
+	assert.Equal(t, 1.5, loaded.Results[0].Time)
+
+	_, err = loadSession("hw", "student", hash, []byte("other"))
+	assert.Error(t, err)
+
+	// forging the results breaks the signature
+	filename, err := sessionFile("hw", "student", hash)
+	require.NoError(t, err)
+	b, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	writeFile(t, filename, strings.Replace(string(b), "1.5", "0.5", 1), 0644)
+	_, err = loadSession("hw", "student", hash, key)
+	assert.Contains(t, err.Error(), "bad signature")
+
+	// unsigned sessions are only loaded without a key
+	require.NoError(t, sess.save(nil))
+	_, err = loadSession("hw", "student", hash, key)
+	assert.Error(t, err)
+	loaded, err = loadSession("hw", "student", hash, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, loaded)
+
+	loaded, err = loadSession("hw", "student", "fedcba9876543210fedcba9876543210", key)
+	assert.NoError(t, err)
+	assert.Nil(t, loaded)
+}
@@ -0,0 +1,153 @@
+package judge
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/NTHU-lsalab/sb/pb"
+)
+
+// Timing policies decide the time submitted for a case run multiple times
+const (
+	TimingPolicyMedian = "median"
+	TimingPolicyMean   = "mean"
+	TimingPolicyMin    = "min"
+)
+
+// Timing configures the adaptive timing mode. Each case is run Warmup times
+// without measuring, then repeatedly until the relative half-width of the 95%
+// confidence interval of the mean falls below TargetCI.
+type Timing struct {
+	Warmup   int
+	MinRuns  int
+	MaxRuns  int
+	TargetCI float64
+}
+
+// Validate checks that the timing options are usable
+func (t *Timing) Validate() error {
+	if t.Warmup < 0 {
+		return fmt.Errorf("negative number of warmup runs: %d", t.Warmup)
+	}
+	if t.MinRuns < 2 {
+		return fmt.Errorf("at least 2 runs are needed for timing statistics, got %d", t.MinRuns)
+	}
+	if t.MaxRuns < t.MinRuns {
+		return fmt.Errorf("maximum runs %d is less than minimum runs %d", t.MaxRuns, t.MinRuns)
+	}
+	if t.TargetCI <= 0 {
+		return fmt.Errorf("target confidence interval must be positive, got %g", t.TargetCI)
+	}
+	return nil
+}
+
+// tTable holds the two-sided 95% critical values of Student's t-distribution
+// for 1 to 30 degrees of freedom
+var tTable = [...]float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+func tValue(df int) float64 {
+	if df <= 0 {
+		return math.Inf(1)
+	}
+	if df <= len(tTable) {
+		return tTable[df-1]
+	}
+	return 1.960
+}
+
+// timingStats computes the statistics of the given times
+func timingStats(times []float64) *pb.TimingStats {
+	n := len(times)
+	if n == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), times...)
+	sort.Float64s(sorted)
+	stats := &pb.TimingStats{
+		Runs: int32(n),
+		Min:  sorted[0],
+		Max:  sorted[n-1],
+	}
+	if n%2 == 1 {
+		stats.Median = sorted[n/2]
+	} else {
+		stats.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	for _, t := range sorted {
+		stats.Mean += t
+	}
+	stats.Mean /= float64(n)
+	if n < 2 {
+		stats.RelativeCi = math.Inf(1)
+		return stats
+	}
+	for _, t := range sorted {
+		stats.Stddev += (t - stats.Mean) * (t - stats.Mean)
+	}
+	stats.Stddev = math.Sqrt(stats.Stddev / float64(n-1))
+	if stats.Mean > 0 {
+		stats.RelativeCi = tValue(n-1) * stats.Stddev / math.Sqrt(float64(n)) / stats.Mean
+	}
+	return stats
+}
+
+// policyTime returns the time to submit according to the timing policy
+func policyTime(stats *pb.TimingStats, policy string) float64 {
+	switch policy {
+	case TimingPolicyMean:
+		return stats.Mean
+	case TimingPolicyMin:
+		return stats.Min
+	default:
+		return stats.Median
+	}
+}
+
+// summarizeRuns picks the result of a case run multiple times.
+// If strict is set, the case fails if any of the runs failed. Otherwise it
+// passes if more than half of the runs passed, picking the median of the passed
+// runs, and fails with the median of the failed runs if not.
+// The time of a passed result follows the timing policy.
+func summarizeRuns(runs []judgeResult, policy string, strict bool) judgeResult {
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].Passed == runs[j].Passed {
+			return runs[i].Time < runs[j].Time
+		}
+		return runs[i].Passed
+	})
+	var passed []float64
+	for _, run := range runs {
+		if run.Passed {
+			passed = append(passed, run.Time)
+		}
+	}
+	var result judgeResult
+	// the passed runs are sorted first
+	switch {
+	case strict && len(passed) < len(runs):
+		result = runs[len(passed)]
+	case len(passed)*2 > len(runs):
+		result = runs[len(passed)/2]
+	default:
+		result = runs[len(passed)+(len(runs)-len(passed))/2]
+	}
+	result.Timing = timingStats(passed)
+	if result.Passed {
+		result.Time = policyTime(result.Timing, policy)
+	}
+	return result
+}
+
+// formatTiming formats the timing statistics for humans
+func formatTiming(stats *pb.TimingStats) string {
+	if stats == nil || stats.Runs < 2 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d runs: mean %.2f, median %.2f, stddev %.3f, min %.2f, ±%.1f%%)",
+		stats.Runs, stats.Mean, stats.Median, stats.Stddev, stats.Min, stats.RelativeCi*100)
+}
@@ -0,0 +1,94 @@
+package judge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimingStats(t *testing.T) {
+	stats := timingStats([]float64{4, 1, 3, 2})
+	assert.EqualValues(t, 4, stats.Runs)
+	assert.Equal(t, 2.5, stats.Mean)
+	assert.Equal(t, 2.5, stats.Median)
+	assert.Equal(t, 1.0, stats.Min)
+	assert.Equal(t, 4.0, stats.Max)
+	assert.InDelta(t, 1.291, stats.Stddev, 0.001)
+	assert.InDelta(t, 3.182*1.291/2/2.5, stats.RelativeCi, 0.001)
+}
+
+func TestSummarizeRunsPolicy(t *testing.T) {
+	runs := func() []judgeResult {
+		return []judgeResult{
+			{Passed: true, Time: 3},
+			{Passed: true, Time: 1},
+			{Passed: true, Time: 5},
+		}
+	}
+	assert.Equal(t, 3.0, summarizeRuns(runs(), TimingPolicyMedian, false).Time)
+	assert.Equal(t, 3.0, summarizeRuns(runs(), TimingPolicyMean, false).Time)
+	assert.Equal(t, 1.0, summarizeRuns(runs(), TimingPolicyMin, false).Time)
+}
+
+func TestSummarizeRunsStrict(t *testing.T) {
+	runs := []judgeResult{
+		{Passed: true, Time: 1, Verdict: "accepted"},
+		{Passed: false, Time: 2, Verdict: "wrong answer"},
+		{Passed: true, Time: 3, Verdict: "accepted"},
+	}
+	assert.True(t, summarizeRuns(runs, TimingPolicyMedian, false).Passed)
+	r := summarizeRuns(runs, TimingPolicyMedian, true)
+	assert.False(t, r.Passed)
+	assert.Equal(t, "wrong answer", r.Verdict)
+	assert.EqualValues(t, 2, r.Timing.Runs)
+}
+
+func TestSummarizeRunsMixed(t *testing.T) {
+	// most runs passed
+	runs := func() []judgeResult {
+		return []judgeResult{
+			{Passed: true, Time: 4, Verdict: "accepted", Details: "slow"},
+			{Passed: false, Time: 1, Verdict: "wrong answer"},
+			{Passed: true, Time: 3, Verdict: "accepted", Details: "fast"},
+			{Passed: true, Time: 5, Verdict: "accepted", Details: "slowest"},
+			{Passed: false, Time: 2, Verdict: "time limit exceeded"},
+		}
+	}
+	r := summarizeRuns(runs(), TimingPolicyMedian, false)
+	assert.True(t, r.Passed)
+	assert.Equal(t, "accepted", r.Verdict)
+	assert.Equal(t, "slow", r.Details)
+	assert.Equal(t, 4.0, r.Time)
+	assert.EqualValues(t, 3, r.Timing.Runs)
+	assert.Equal(t, 3.0, summarizeRuns(runs(), TimingPolicyMin, false).Time)
+
+	r = summarizeRuns(runs(), TimingPolicyMedian, true)
+	assert.False(t, r.Passed)
+	assert.Equal(t, "wrong answer", r.Verdict)
+
+	// most runs failed, so a lucky run does not pass the case
+	runs = func() []judgeResult {
+		return []judgeResult{
+			{Passed: false, Time: 3, Verdict: "wrong answer"},
+			{Passed: true, Time: 1, Verdict: "accepted"},
+			{Passed: false, Time: 1, Verdict: "runtime error"},
+			{Passed: true, Time: 1, Verdict: "accepted"},
+			{Passed: false, Time: 2, Verdict: "time limit exceeded"},
+		}
+	}
+	r = summarizeRuns(runs(), TimingPolicyMedian, false)
+	assert.False(t, r.Passed)
+	assert.Equal(t, "time limit exceeded", r.Verdict)
+	assert.Equal(t, 2.0, r.Time)
+	assert.EqualValues(t, 2, r.Timing.Runs)
+
+	failed := []judgeResult{
+		{Passed: false, Time: 3, Verdict: "wrong answer"},
+		{Passed: false, Time: 1, Verdict: "runtime error"},
+		{Passed: false, Time: 2, Verdict: "time limit exceeded"},
+	}
+	r = summarizeRuns(failed, TimingPolicyMedian, false)
+	assert.False(t, r.Passed)
+	assert.Equal(t, "time limit exceeded", r.Verdict)
+	assert.Equal(t, 2.0, r.Time)
+}
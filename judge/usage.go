@@ -0,0 +1,59 @@
+package judge
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/NTHU-lsalab/sb/pb"
+)
+
+// resourceUsage is the resources used by a runner process tree
+type resourceUsage struct {
+	UserTime float64
+	SysTime  float64
+	MaxRSS   int64 // KiB
+}
+
+// rusageOf returns the resource usage of an exited process and its waited-for descendants
+func rusageOf(state *os.ProcessState) resourceUsage {
+	if state == nil {
+		return resourceUsage{}
+	}
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return resourceUsage{}
+	}
+	return resourceUsage{
+		UserTime: state.UserTime().Seconds(),
+		SysTime:  state.SystemTime().Seconds(),
+		MaxRSS:   rusage.Maxrss,
+	}
+}
+
+// CPUTime returns the total user and system CPU time
+func (u resourceUsage) CPUTime() float64 {
+	return u.UserTime + u.SysTime
+}
+
+func (u resourceUsage) toProto() *pb.ResourceUsage {
+	return &pb.ResourceUsage{
+		UserTime: u.UserTime,
+		SysTime:  u.SysTime,
+		MaxRss:   u.MaxRSS,
+	}
+}
+
+// formatKiB formats a memory size in KiB in a human readable form
+func formatKiB(kib int64) string {
+	switch {
+	case kib <= 0:
+		return "-"
+	case kib < 1024:
+		return fmt.Sprintf("%dK", kib)
+	case kib < 1024*1024:
+		return fmt.Sprintf("%.1fM", float64(kib)/1024)
+	default:
+		return fmt.Sprintf("%.2fG", float64(kib)/(1024*1024))
+	}
+}
@@ -0,0 +1,27 @@
+package judge
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRusageOf(t *testing.T) {
+	assert.Equal(t, resourceUsage{}, rusageOf(nil))
+
+	cmd := exec.Command("/bin/sh", "-c", "i=0; while [ $i -lt 20000 ]; do i=$((i+1)); done")
+	require.NoError(t, cmd.Run())
+	usage := rusageOf(cmd.ProcessState)
+	assert.True(t, usage.CPUTime() > 0)
+	assert.True(t, usage.MaxRSS > 0)
+	assert.Equal(t, usage.UserTime+usage.SysTime, usage.CPUTime())
+}
+
+func TestFormatKiB(t *testing.T) {
+	assert.Equal(t, "-", formatKiB(0))
+	assert.Equal(t, "512K", formatKiB(512))
+	assert.Equal(t, "1.5M", formatKiB(1536))
+	assert.Equal(t, "2.00G", formatKiB(2*1024*1024))
+}
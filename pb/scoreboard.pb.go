@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
+// 	protoc-gen-go v1.25.0
 // 	protoc        v3.12.4
 // source: scoreboard.proto
 
@@ -29,6 +29,55 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type SubmissionRecord_Outcome int32
+
+const (
+	SubmissionRecord_CREATED     SubmissionRecord_Outcome = 0
+	SubmissionRecord_UPDATED     SubmissionRecord_Outcome = 1
+	SubmissionRecord_NOT_UPDATED SubmissionRecord_Outcome = 2
+)
+
+// Enum value maps for SubmissionRecord_Outcome.
+var (
+	SubmissionRecord_Outcome_name = map[int32]string{
+		0: "CREATED",
+		1: "UPDATED",
+		2: "NOT_UPDATED",
+	}
+	SubmissionRecord_Outcome_value = map[string]int32{
+		"CREATED":     0,
+		"UPDATED":     1,
+		"NOT_UPDATED": 2,
+	}
+)
+
+func (x SubmissionRecord_Outcome) Enum() *SubmissionRecord_Outcome {
+	p := new(SubmissionRecord_Outcome)
+	*p = x
+	return p
+}
+
+func (x SubmissionRecord_Outcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubmissionRecord_Outcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_scoreboard_proto_enumTypes[0].Descriptor()
+}
+
+func (SubmissionRecord_Outcome) Type() protoreflect.EnumType {
+	return &file_scoreboard_proto_enumTypes[0]
+}
+
+func (x SubmissionRecord_Outcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubmissionRecord_Outcome.Descriptor instead.
+func (SubmissionRecord_Outcome) EnumDescriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{8, 0}
+}
+
 type QueryHomeworkRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -76,21 +125,17 @@ func (x *QueryHomeworkRequest) GetName() string {
 	return ""
 }
 
-type Homework struct {
+type QueryCaseTimesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name        string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Target      string        `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
-	Runner      string        `protobuf:"bytes,3,opt,name=runner,proto3" json:"runner,omitempty"`
-	Files       []*SourceFile `protobuf:"bytes,4,rep,name=files,proto3" json:"files,omitempty"`
-	PenaltyTime float64       `protobuf:"fixed64,5,opt,name=penalty_time,json=penaltyTime,proto3" json:"penalty_time,omitempty"`
-	Cases       []string      `protobuf:"bytes,6,rep,name=cases,proto3" json:"cases,omitempty"`
+	Homework string `protobuf:"bytes,1,opt,name=homework,proto3" json:"homework,omitempty"`
+	User     string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
 }
 
-func (x *Homework) Reset() {
-	*x = Homework{}
+func (x *QueryCaseTimesRequest) Reset() {
+	*x = QueryCaseTimesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_scoreboard_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -98,13 +143,13 @@ func (x *Homework) Reset() {
 	}
 }
 
-func (x *Homework) String() string {
+func (x *QueryCaseTimesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Homework) ProtoMessage() {}
+func (*QueryCaseTimesRequest) ProtoMessage() {}
 
-func (x *Homework) ProtoReflect() protoreflect.Message {
+func (x *QueryCaseTimesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_scoreboard_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -116,64 +161,36 @@ func (x *Homework) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Homework.ProtoReflect.Descriptor instead.
-func (*Homework) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryCaseTimesRequest.ProtoReflect.Descriptor instead.
+func (*QueryCaseTimesRequest) Descriptor() ([]byte, []int) {
 	return file_scoreboard_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *Homework) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *Homework) GetTarget() string {
+func (x *QueryCaseTimesRequest) GetHomework() string {
 	if x != nil {
-		return x.Target
+		return x.Homework
 	}
 	return ""
 }
 
-func (x *Homework) GetRunner() string {
+func (x *QueryCaseTimesRequest) GetUser() string {
 	if x != nil {
-		return x.Runner
+		return x.User
 	}
 	return ""
 }
 
-func (x *Homework) GetFiles() []*SourceFile {
-	if x != nil {
-		return x.Files
-	}
-	return nil
-}
-
-func (x *Homework) GetPenaltyTime() float64 {
-	if x != nil {
-		return x.PenaltyTime
-	}
-	return 0
-}
-
-func (x *Homework) GetCases() []string {
-	if x != nil {
-		return x.Cases
-	}
-	return nil
-}
-
-type SourceFile struct {
+// CaseTimes maps case names to the time of the stored results
+type CaseTimes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Fallback string `protobuf:"bytes,2,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	Times map[string]float64 `protobuf:"bytes,1,rep,name=times,proto3" json:"times,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
 }
 
-func (x *SourceFile) Reset() {
-	*x = SourceFile{}
+func (x *CaseTimes) Reset() {
+	*x = CaseTimes{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_scoreboard_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -181,13 +198,13 @@ func (x *SourceFile) Reset() {
 	}
 }
 
-func (x *SourceFile) String() string {
+func (x *CaseTimes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SourceFile) ProtoMessage() {}
+func (*CaseTimes) ProtoMessage() {}
 
-func (x *SourceFile) ProtoReflect() protoreflect.Message {
+func (x *CaseTimes) ProtoReflect() protoreflect.Message {
 	mi := &file_scoreboard_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -199,35 +216,41 @@ func (x *SourceFile) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SourceFile.ProtoReflect.Descriptor instead.
-func (*SourceFile) Descriptor() ([]byte, []int) {
+// Deprecated: Use CaseTimes.ProtoReflect.Descriptor instead.
+func (*CaseTimes) Descriptor() ([]byte, []int) {
 	return file_scoreboard_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *SourceFile) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *SourceFile) GetFallback() string {
+func (x *CaseTimes) GetTimes() map[string]float64 {
 	if x != nil {
-		return x.Fallback
+		return x.Times
 	}
-	return ""
+	return nil
 }
 
-type SubmissionReply struct {
+type Homework struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Name          string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Target        string          `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	Runner        string          `protobuf:"bytes,3,opt,name=runner,proto3" json:"runner,omitempty"`
+	Files         []*SourceFile   `protobuf:"bytes,4,rep,name=files,proto3" json:"files,omitempty"`
+	PenaltyTime   float64         `protobuf:"fixed64,5,opt,name=penalty_time,json=penaltyTime,proto3" json:"penalty_time,omitempty"`
+	Cases         []string        `protobuf:"bytes,6,rep,name=cases,proto3" json:"cases,omitempty"`
+	MetricColumns []*MetricColumn `protobuf:"bytes,7,rep,name=metric_columns,json=metricColumns,proto3" json:"metric_columns,omitempty"`
+	Limits        *Limits         `protobuf:"bytes,8,opt,name=limits,proto3" json:"limits,omitempty"`
+	// the time submitted for a case run multiple times: median, mean or min
+	TimingPolicy string `protobuf:"bytes,9,opt,name=timing_policy,json=timingPolicy,proto3" json:"timing_policy,omitempty"`
+	// expected time of the cases, used for scheduling when the user has no previous results
+	ReferenceTimes map[string]float64 `protobuf:"bytes,10,rep,name=reference_times,json=referenceTimes,proto3" json:"reference_times,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	// build version of the server, set in the replies of QueryHomework
+	ServerVersion string `protobuf:"bytes,11,opt,name=server_version,json=serverVersion,proto3" json:"server_version,omitempty"`
 }
 
-func (x *SubmissionReply) Reset() {
-	*x = SubmissionReply{}
+func (x *Homework) Reset() {
+	*x = Homework{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_scoreboard_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -235,13 +258,13 @@ func (x *SubmissionReply) Reset() {
 	}
 }
 
-func (x *SubmissionReply) String() string {
+func (x *Homework) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubmissionReply) ProtoMessage() {}
+func (*Homework) ProtoMessage() {}
 
-func (x *SubmissionReply) ProtoReflect() protoreflect.Message {
+func (x *Homework) ProtoReflect() protoreflect.Message {
 	mi := &file_scoreboard_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -253,29 +276,113 @@ func (x *SubmissionReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubmissionReply.ProtoReflect.Descriptor instead.
-func (*SubmissionReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use Homework.ProtoReflect.Descriptor instead.
+func (*Homework) Descriptor() ([]byte, []int) {
 	return file_scoreboard_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *SubmissionReply) GetMessage() string {
+func (x *Homework) GetName() string {
 	if x != nil {
-		return x.Message
+		return x.Name
 	}
 	return ""
 }
 
-type StoredSubmission struct {
+func (x *Homework) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *Homework) GetRunner() string {
+	if x != nil {
+		return x.Runner
+	}
+	return ""
+}
+
+func (x *Homework) GetFiles() []*SourceFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *Homework) GetPenaltyTime() float64 {
+	if x != nil {
+		return x.PenaltyTime
+	}
+	return 0
+}
+
+func (x *Homework) GetCases() []string {
+	if x != nil {
+		return x.Cases
+	}
+	return nil
+}
+
+func (x *Homework) GetMetricColumns() []*MetricColumn {
+	if x != nil {
+		return x.MetricColumns
+	}
+	return nil
+}
+
+func (x *Homework) GetLimits() *Limits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *Homework) GetTimingPolicy() string {
+	if x != nil {
+		return x.TimingPolicy
+	}
+	return ""
+}
+
+func (x *Homework) GetReferenceTimes() map[string]float64 {
+	if x != nil {
+		return x.ReferenceTimes
+	}
+	return nil
+}
+
+func (x *Homework) GetServerVersion() string {
+	if x != nil {
+		return x.ServerVersion
+	}
+	return ""
+}
+
+// Limits are resource limits applied to the runners of a homework.
+// Zero means unlimited.
+type Limits struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	User    string    `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	Results []*Result `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
-}
-
-func (x *StoredSubmission) Reset() {
-	*x = StoredSubmission{}
+	// address space (RLIMIT_AS) in MiB
+	AddressSpace int64 `protobuf:"varint,1,opt,name=address_space,json=addressSpace,proto3" json:"address_space,omitempty"`
+	// CPU time (RLIMIT_CPU) in seconds
+	CpuTime int64 `protobuf:"varint,2,opt,name=cpu_time,json=cpuTime,proto3" json:"cpu_time,omitempty"`
+	// number of processes of the user (RLIMIT_NPROC)
+	Processes int64 `protobuf:"varint,3,opt,name=processes,proto3" json:"processes,omitempty"`
+	// number of open files (RLIMIT_NOFILE)
+	OpenFiles int64 `protobuf:"varint,4,opt,name=open_files,json=openFiles,proto3" json:"open_files,omitempty"`
+	// size of files written (RLIMIT_FSIZE) in MiB
+	FileSize int64 `protobuf:"varint,5,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	// peak memory in MiB, exceeding it results in memory limit exceeded
+	Memory int64 `protobuf:"varint,6,opt,name=memory,proto3" json:"memory,omitempty"`
+	// run the runner in an unprivileged user, mount and network namespace
+	Sandbox bool `protobuf:"varint,7,opt,name=sandbox,proto3" json:"sandbox,omitempty"`
+}
+
+func (x *Limits) Reset() {
+	*x = Limits{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_scoreboard_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -283,13 +390,13 @@ func (x *StoredSubmission) Reset() {
 	}
 }
 
-func (x *StoredSubmission) String() string {
+func (x *Limits) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StoredSubmission) ProtoMessage() {}
+func (*Limits) ProtoMessage() {}
 
-func (x *StoredSubmission) ProtoReflect() protoreflect.Message {
+func (x *Limits) ProtoReflect() protoreflect.Message {
 	mi := &file_scoreboard_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -301,39 +408,76 @@ func (x *StoredSubmission) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StoredSubmission.ProtoReflect.Descriptor instead.
-func (*StoredSubmission) Descriptor() ([]byte, []int) {
+// Deprecated: Use Limits.ProtoReflect.Descriptor instead.
+func (*Limits) Descriptor() ([]byte, []int) {
 	return file_scoreboard_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *StoredSubmission) GetUser() string {
+func (x *Limits) GetAddressSpace() int64 {
 	if x != nil {
-		return x.User
+		return x.AddressSpace
 	}
-	return ""
+	return 0
 }
 
-func (x *StoredSubmission) GetResults() []*Result {
+func (x *Limits) GetCpuTime() int64 {
 	if x != nil {
-		return x.Results
+		return x.CpuTime
 	}
-	return nil
+	return 0
 }
 
-type UserSubmission struct {
+func (x *Limits) GetProcesses() int64 {
+	if x != nil {
+		return x.Processes
+	}
+	return 0
+}
+
+func (x *Limits) GetOpenFiles() int64 {
+	if x != nil {
+		return x.OpenFiles
+	}
+	return 0
+}
+
+func (x *Limits) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
+}
+
+func (x *Limits) GetMemory() int64 {
+	if x != nil {
+		return x.Memory
+	}
+	return 0
+}
+
+func (x *Limits) GetSandbox() bool {
+	if x != nil {
+		return x.Sandbox
+	}
+	return false
+}
+
+// MetricColumn is an extra column on the scoreboard that summarizes a
+// metric reported by the runner over the passed cases
+type MetricColumn struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	// string secret = 2;
-	Homework string    `protobuf:"bytes,3,opt,name=homework,proto3" json:"homework,omitempty"`
-	Results  []*Result `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
-	Code     []byte    `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	Metric string `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	Title  string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Format string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	// one of sum, mean, min, max
+	Aggregate string `protobuf:"bytes,4,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
 }
 
-func (x *UserSubmission) Reset() {
-	*x = UserSubmission{}
+func (x *MetricColumn) Reset() {
+	*x = MetricColumn{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_scoreboard_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -341,13 +485,13 @@ func (x *UserSubmission) Reset() {
 	}
 }
 
-func (x *UserSubmission) String() string {
+func (x *MetricColumn) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserSubmission) ProtoMessage() {}
+func (*MetricColumn) ProtoMessage() {}
 
-func (x *UserSubmission) ProtoReflect() protoreflect.Message {
+func (x *MetricColumn) ProtoReflect() protoreflect.Message {
 	mi := &file_scoreboard_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -359,52 +503,50 @@ func (x *UserSubmission) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserSubmission.ProtoReflect.Descriptor instead.
-func (*UserSubmission) Descriptor() ([]byte, []int) {
+// Deprecated: Use MetricColumn.ProtoReflect.Descriptor instead.
+func (*MetricColumn) Descriptor() ([]byte, []int) {
 	return file_scoreboard_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *UserSubmission) GetUser() string {
+func (x *MetricColumn) GetMetric() string {
 	if x != nil {
-		return x.User
+		return x.Metric
 	}
 	return ""
 }
 
-func (x *UserSubmission) GetHomework() string {
+func (x *MetricColumn) GetTitle() string {
 	if x != nil {
-		return x.Homework
+		return x.Title
 	}
 	return ""
 }
 
-func (x *UserSubmission) GetResults() []*Result {
+func (x *MetricColumn) GetFormat() string {
 	if x != nil {
-		return x.Results
+		return x.Format
 	}
-	return nil
+	return ""
 }
 
-func (x *UserSubmission) GetCode() []byte {
+func (x *MetricColumn) GetAggregate() string {
 	if x != nil {
-		return x.Code
+		return x.Aggregate
 	}
-	return nil
+	return ""
 }
 
-type Result struct {
+type SourceFile struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Case    string  `protobuf:"bytes,1,opt,name=case,proto3" json:"case,omitempty"`
-	Passed  bool    `protobuf:"varint,2,opt,name=passed,proto3" json:"passed,omitempty"`
-	Time    float64 `protobuf:"fixed64,3,opt,name=time,proto3" json:"time,omitempty"`
-	Verdict string  `protobuf:"bytes,4,opt,name=verdict,proto3" json:"verdict,omitempty"`
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Fallback string `protobuf:"bytes,2,opt,name=fallback,proto3" json:"fallback,omitempty"`
 }
 
-func (x *Result) Reset() {
-	*x = Result{}
+func (x *SourceFile) Reset() {
+	*x = SourceFile{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_scoreboard_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -412,13 +554,13 @@ func (x *Result) Reset() {
 	}
 }
 
-func (x *Result) String() string {
+func (x *SourceFile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Result) ProtoMessage() {}
+func (*SourceFile) ProtoMessage() {}
 
-func (x *Result) ProtoReflect() protoreflect.Message {
+func (x *SourceFile) ProtoReflect() protoreflect.Message {
 	mi := &file_scoreboard_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -430,35 +572,1985 @@ func (x *Result) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Result.ProtoReflect.Descriptor instead.
-func (*Result) Descriptor() ([]byte, []int) {
-	return file_scoreboard_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use SourceFile.ProtoReflect.Descriptor instead.
+func (*SourceFile) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SourceFile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SourceFile) GetFallback() string {
+	if x != nil {
+		return x.Fallback
+	}
+	return ""
+}
+
+type SubmissionReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string            `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Record  *SubmissionRecord `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	// the record signed by the server, absent if the server has no signing key
+	Receipt *Receipt `protobuf:"bytes,3,opt,name=receipt,proto3" json:"receipt,omitempty"`
+}
+
+func (x *SubmissionReply) Reset() {
+	*x = SubmissionReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmissionReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmissionReply) ProtoMessage() {}
+
+func (x *SubmissionReply) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmissionReply.ProtoReflect.Descriptor instead.
+func (*SubmissionReply) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SubmissionReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SubmissionReply) GetRecord() *SubmissionRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *SubmissionReply) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+// SubmissionRecord describes a submission as accepted by the server
+type SubmissionRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Homework string `protobuf:"bytes,2,opt,name=homework,proto3" json:"homework,omitempty"`
+	User     string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	// server time of the submission, in nanoseconds since the unix epoch
+	TimeUnixNano int64 `protobuf:"varint,4,opt,name=time_unix_nano,json=timeUnixNano,proto3" json:"time_unix_nano,omitempty"`
+	// hex encoded SHA-256 of the submitted results
+	ResultsHash string `protobuf:"bytes,5,opt,name=results_hash,json=resultsHash,proto3" json:"results_hash,omitempty"`
+	// code_hash of the submission as reported by the client, or the hex encoded
+	// SHA-256 of its code. It is not checked by the server.
+	CodeHash string                   `protobuf:"bytes,6,opt,name=code_hash,json=codeHash,proto3" json:"code_hash,omitempty"`
+	Outcome  SubmissionRecord_Outcome `protobuf:"varint,7,opt,name=outcome,proto3,enum=pb.SubmissionRecord_Outcome" json:"outcome,omitempty"`
+	// the stored score before the submission, absent for the first submission
+	OldScore *Score `protobuf:"bytes,8,opt,name=old_score,json=oldScore,proto3" json:"old_score,omitempty"`
+	NewScore *Score `protobuf:"bytes,9,opt,name=new_score,json=newScore,proto3" json:"new_score,omitempty"`
+}
+
+func (x *SubmissionRecord) Reset() {
+	*x = SubmissionRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmissionRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmissionRecord) ProtoMessage() {}
+
+func (x *SubmissionRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmissionRecord.ProtoReflect.Descriptor instead.
+func (*SubmissionRecord) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SubmissionRecord) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SubmissionRecord) GetHomework() string {
+	if x != nil {
+		return x.Homework
+	}
+	return ""
+}
+
+func (x *SubmissionRecord) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *SubmissionRecord) GetTimeUnixNano() int64 {
+	if x != nil {
+		return x.TimeUnixNano
+	}
+	return 0
+}
+
+func (x *SubmissionRecord) GetResultsHash() string {
+	if x != nil {
+		return x.ResultsHash
+	}
+	return ""
+}
+
+func (x *SubmissionRecord) GetCodeHash() string {
+	if x != nil {
+		return x.CodeHash
+	}
+	return ""
+}
+
+func (x *SubmissionRecord) GetOutcome() SubmissionRecord_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return SubmissionRecord_CREATED
+}
+
+func (x *SubmissionRecord) GetOldScore() *Score {
+	if x != nil {
+		return x.OldScore
+	}
+	return nil
+}
+
+func (x *SubmissionRecord) GetNewScore() *Score {
+	if x != nil {
+		return x.NewScore
+	}
+	return nil
+}
+
+type Score struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NumPassed   int32   `protobuf:"varint,1,opt,name=num_passed,json=numPassed,proto3" json:"num_passed,omitempty"`
+	TotalTime   float64 `protobuf:"fixed64,2,opt,name=total_time,json=totalTime,proto3" json:"total_time,omitempty"`
+	PenaltyTime float64 `protobuf:"fixed64,3,opt,name=penalty_time,json=penaltyTime,proto3" json:"penalty_time,omitempty"`
+}
+
+func (x *Score) Reset() {
+	*x = Score{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Score) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Score) ProtoMessage() {}
+
+func (x *Score) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Score.ProtoReflect.Descriptor instead.
+func (*Score) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Score) GetNumPassed() int32 {
+	if x != nil {
+		return x.NumPassed
+	}
+	return 0
+}
+
+func (x *Score) GetTotalTime() float64 {
+	if x != nil {
+		return x.TotalTime
+	}
+	return 0
+}
+
+func (x *Score) GetPenaltyTime() float64 {
+	if x != nil {
+		return x.PenaltyTime
+	}
+	return 0
+}
+
+// Receipt is a SubmissionRecord signed by the server
+type Receipt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// the serialized SubmissionRecord
+	Record []byte `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// ed25519 signature of record
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	// hex encoded SHA-256 of the public key of the server, truncated to 8 bytes
+	KeyId string `protobuf:"bytes,3,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Receipt) GetRecord() []byte {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *Receipt) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *Receipt) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+// HomeworkList lists the homeworks of the scoreboard
+type HomeworkList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Homeworks []string `protobuf:"bytes,1,rep,name=homeworks,proto3" json:"homeworks,omitempty"`
+}
+
+func (x *HomeworkList) Reset() {
+	*x = HomeworkList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HomeworkList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HomeworkList) ProtoMessage() {}
+
+func (x *HomeworkList) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HomeworkList.ProtoReflect.Descriptor instead.
+func (*HomeworkList) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HomeworkList) GetHomeworks() []string {
+	if x != nil {
+		return x.Homeworks
+	}
+	return nil
+}
+
+// Board is the ranking of a homework, as shown on the scoreboard
+type Board struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Homework string      `protobuf:"bytes,1,opt,name=homework,proto3" json:"homework,omitempty"`
+	Rows     []*BoardRow `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+}
+
+func (x *Board) Reset() {
+	*x = Board{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Board) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Board) ProtoMessage() {}
+
+func (x *Board) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Board.ProtoReflect.Descriptor instead.
+func (*Board) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Board) GetHomework() string {
+	if x != nil {
+		return x.Homework
+	}
+	return ""
+}
+
+func (x *Board) GetRows() []*BoardRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type BoardRow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// 0 for users who are not ranked
+	Rank        int32   `protobuf:"varint,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	NumPassed   int32   `protobuf:"varint,3,opt,name=num_passed,json=numPassed,proto3" json:"num_passed,omitempty"`
+	TotalTime   float64 `protobuf:"fixed64,4,opt,name=total_time,json=totalTime,proto3" json:"total_time,omitempty"`
+	PenaltyTime float64 `protobuf:"fixed64,5,opt,name=penalty_time,json=penaltyTime,proto3" json:"penalty_time,omitempty"`
+	// the results as shown publicly, without details
+	Results      []*Result `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`
+	Disqualified bool      `protobuf:"varint,7,opt,name=disqualified,proto3" json:"disqualified,omitempty"`
+}
+
+func (x *BoardRow) Reset() {
+	*x = BoardRow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BoardRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardRow) ProtoMessage() {}
+
+func (x *BoardRow) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardRow.ProtoReflect.Descriptor instead.
+func (*BoardRow) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BoardRow) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *BoardRow) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *BoardRow) GetNumPassed() int32 {
+	if x != nil {
+		return x.NumPassed
+	}
+	return 0
+}
+
+func (x *BoardRow) GetTotalTime() float64 {
+	if x != nil {
+		return x.TotalTime
+	}
+	return 0
+}
+
+func (x *BoardRow) GetPenaltyTime() float64 {
+	if x != nil {
+		return x.PenaltyTime
+	}
+	return 0
+}
+
+func (x *BoardRow) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BoardRow) GetDisqualified() bool {
+	if x != nil {
+		return x.Disqualified
+	}
+	return false
+}
+
+type WatchBoardRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Homework string `protobuf:"bytes,1,opt,name=homework,proto3" json:"homework,omitempty"`
+	// resume after the event with this token instead of starting with a snapshot
+	ResumeToken string `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+}
+
+func (x *WatchBoardRequest) Reset() {
+	*x = WatchBoardRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchBoardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBoardRequest) ProtoMessage() {}
+
+func (x *WatchBoardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBoardRequest.ProtoReflect.Descriptor instead.
+func (*WatchBoardRequest) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchBoardRequest) GetHomework() string {
+	if x != nil {
+		return x.Homework
+	}
+	return ""
+}
+
+func (x *WatchBoardRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type BoardEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// token to resume watching after this event
+	ResumeToken string `protobuf:"bytes,1,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	// Types that are assignable to Event:
+	//	*BoardEvent_Snapshot
+	//	*BoardEvent_Update
+	Event isBoardEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *BoardEvent) Reset() {
+	*x = BoardEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BoardEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardEvent) ProtoMessage() {}
+
+func (x *BoardEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardEvent.ProtoReflect.Descriptor instead.
+func (*BoardEvent) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BoardEvent) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (m *BoardEvent) GetEvent() isBoardEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *BoardEvent) GetSnapshot() *Board {
+	if x, ok := x.GetEvent().(*BoardEvent_Snapshot); ok {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *BoardEvent) GetUpdate() *BoardUpdate {
+	if x, ok := x.GetEvent().(*BoardEvent_Update); ok {
+		return x.Update
+	}
+	return nil
+}
+
+type isBoardEvent_Event interface {
+	isBoardEvent_Event()
+}
+
+type BoardEvent_Snapshot struct {
+	// the whole board
+	Snapshot *Board `protobuf:"bytes,2,opt,name=snapshot,proto3,oneof"`
+}
+
+type BoardEvent_Update struct {
+	// rows that changed or were removed
+	Update *BoardUpdate `protobuf:"bytes,3,opt,name=update,proto3,oneof"`
+}
+
+func (*BoardEvent_Snapshot) isBoardEvent_Event() {}
+
+func (*BoardEvent_Update) isBoardEvent_Event() {}
+
+type BoardUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// rows that changed, replacing the rows of the same users
+	Rows []*BoardRow `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	// users whose rows were removed from the board, such as by sbctl delete
+	RemovedUsers []string `protobuf:"bytes,2,rep,name=removed_users,json=removedUsers,proto3" json:"removed_users,omitempty"`
+}
+
+func (x *BoardUpdate) Reset() {
+	*x = BoardUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BoardUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardUpdate) ProtoMessage() {}
+
+func (x *BoardUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardUpdate.ProtoReflect.Descriptor instead.
+func (*BoardUpdate) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BoardUpdate) GetRows() []*BoardRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *BoardUpdate) GetRemovedUsers() []string {
+	if x != nil {
+		return x.RemovedUsers
+	}
+	return nil
+}
+
+type StoredSubmission struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User    string    `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Results []*Result `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	// id and server time of the submission
+	Id           string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	TimeUnixNano int64  `protobuf:"varint,4,opt,name=time_unix_nano,json=timeUnixNano,proto3" json:"time_unix_nano,omitempty"`
+	// disqualified users are not ranked
+	Disqualified bool    `protobuf:"varint,5,opt,name=disqualified,proto3" json:"disqualified,omitempty"`
+	Notes        []*Note `protobuf:"bytes,6,rep,name=notes,proto3" json:"notes,omitempty"`
+}
+
+func (x *StoredSubmission) Reset() {
+	*x = StoredSubmission{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredSubmission) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredSubmission) ProtoMessage() {}
+
+func (x *StoredSubmission) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredSubmission.ProtoReflect.Descriptor instead.
+func (*StoredSubmission) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StoredSubmission) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *StoredSubmission) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *StoredSubmission) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StoredSubmission) GetTimeUnixNano() int64 {
+	if x != nil {
+		return x.TimeUnixNano
+	}
+	return 0
+}
+
+func (x *StoredSubmission) GetDisqualified() bool {
+	if x != nil {
+		return x.Disqualified
+	}
+	return false
+}
+
+func (x *StoredSubmission) GetNotes() []*Note {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+// Note is a remark of an admin on the entry of a user
+type Note struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TimeUnixNano int64 `protobuf:"varint,1,opt,name=time_unix_nano,json=timeUnixNano,proto3" json:"time_unix_nano,omitempty"`
+	// identity of the admin, as in the audit log
+	Peer string `protobuf:"bytes,2,opt,name=peer,proto3" json:"peer,omitempty"`
+	Text string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *Note) Reset() {
+	*x = Note{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Note) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Note) ProtoMessage() {}
+
+func (x *Note) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Note.ProtoReflect.Descriptor instead.
+func (*Note) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Note) GetTimeUnixNano() int64 {
+	if x != nil {
+		return x.TimeUnixNano
+	}
+	return 0
+}
+
+func (x *Note) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *Note) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type UserSubmission struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// string secret = 2;
+	Homework string    `protobuf:"bytes,3,opt,name=homework,proto3" json:"homework,omitempty"`
+	Results  []*Result `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	Code     []byte    `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	// hex encoded SHA-256 of the source files, used instead of hashing code
+	CodeHash string `protobuf:"bytes,6,opt,name=code_hash,json=codeHash,proto3" json:"code_hash,omitempty"`
+}
+
+func (x *UserSubmission) Reset() {
+	*x = UserSubmission{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserSubmission) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSubmission) ProtoMessage() {}
+
+func (x *UserSubmission) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserSubmission.ProtoReflect.Descriptor instead.
+func (*UserSubmission) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UserSubmission) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *UserSubmission) GetHomework() string {
+	if x != nil {
+		return x.Homework
+	}
+	return ""
+}
+
+func (x *UserSubmission) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *UserSubmission) GetCode() []byte {
+	if x != nil {
+		return x.Code
+	}
+	return nil
+}
+
+func (x *UserSubmission) GetCodeHash() string {
+	if x != nil {
+		return x.CodeHash
+	}
+	return ""
+}
+
+type Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Case    string  `protobuf:"bytes,1,opt,name=case,proto3" json:"case,omitempty"`
+	Passed  bool    `protobuf:"varint,2,opt,name=passed,proto3" json:"passed,omitempty"`
+	Time    float64 `protobuf:"fixed64,3,opt,name=time,proto3" json:"time,omitempty"`
+	Verdict string  `protobuf:"bytes,4,opt,name=verdict,proto3" json:"verdict,omitempty"`
+	Details string  `protobuf:"bytes,5,opt,name=details,proto3" json:"details,omitempty"`
+	// peak memory usage in KiB
+	Memory  int64              `protobuf:"varint,6,opt,name=memory,proto3" json:"memory,omitempty"`
+	CpuTime float64            `protobuf:"fixed64,7,opt,name=cpu_time,json=cpuTime,proto3" json:"cpu_time,omitempty"`
+	Metrics map[string]float64 `protobuf:"bytes,8,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Node    string             `protobuf:"bytes,9,opt,name=node,proto3" json:"node,omitempty"`
+	// structured details reported by the runner, encoded in JSON
+	StructuredDetails string `protobuf:"bytes,10,opt,name=structured_details,json=structuredDetails,proto3" json:"structured_details,omitempty"`
+	// resources used by the runner process tree, measured by the judge
+	RunnerUsage *ResourceUsage `protobuf:"bytes,11,opt,name=runner_usage,json=runnerUsage,proto3" json:"runner_usage,omitempty"`
+	// statistics of the passed runs when the case is run multiple times
+	Timing *TimingStats `protobuf:"bytes,12,opt,name=timing,proto3" json:"timing,omitempty"`
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Result) GetCase() string {
+	if x != nil {
+		return x.Case
+	}
+	return ""
+}
+
+func (x *Result) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *Result) GetTime() float64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Result) GetVerdict() string {
+	if x != nil {
+		return x.Verdict
+	}
+	return ""
+}
+
+func (x *Result) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *Result) GetMemory() int64 {
+	if x != nil {
+		return x.Memory
+	}
+	return 0
+}
+
+func (x *Result) GetCpuTime() float64 {
+	if x != nil {
+		return x.CpuTime
+	}
+	return 0
+}
+
+func (x *Result) GetMetrics() map[string]float64 {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *Result) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *Result) GetStructuredDetails() string {
+	if x != nil {
+		return x.StructuredDetails
+	}
+	return ""
+}
+
+func (x *Result) GetRunnerUsage() *ResourceUsage {
+	if x != nil {
+		return x.RunnerUsage
+	}
+	return nil
+}
+
+func (x *Result) GetTiming() *TimingStats {
+	if x != nil {
+		return x.Timing
+	}
+	return nil
+}
+
+type TimingStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Runs   int32   `protobuf:"varint,1,opt,name=runs,proto3" json:"runs,omitempty"`
+	Mean   float64 `protobuf:"fixed64,2,opt,name=mean,proto3" json:"mean,omitempty"`
+	Median float64 `protobuf:"fixed64,3,opt,name=median,proto3" json:"median,omitempty"`
+	Stddev float64 `protobuf:"fixed64,4,opt,name=stddev,proto3" json:"stddev,omitempty"`
+	Min    float64 `protobuf:"fixed64,5,opt,name=min,proto3" json:"min,omitempty"`
+	Max    float64 `protobuf:"fixed64,6,opt,name=max,proto3" json:"max,omitempty"`
+	// half-width of the 95% confidence interval of the mean, relative to the mean
+	RelativeCi float64 `protobuf:"fixed64,7,opt,name=relative_ci,json=relativeCi,proto3" json:"relative_ci,omitempty"`
+}
+
+func (x *TimingStats) Reset() {
+	*x = TimingStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimingStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimingStats) ProtoMessage() {}
+
+func (x *TimingStats) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimingStats.ProtoReflect.Descriptor instead.
+func (*TimingStats) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TimingStats) GetRuns() int32 {
+	if x != nil {
+		return x.Runs
+	}
+	return 0
+}
+
+func (x *TimingStats) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *TimingStats) GetMedian() float64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *TimingStats) GetStddev() float64 {
+	if x != nil {
+		return x.Stddev
+	}
+	return 0
+}
+
+func (x *TimingStats) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *TimingStats) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *TimingStats) GetRelativeCi() float64 {
+	if x != nil {
+		return x.RelativeCi
+	}
+	return 0
+}
+
+type ResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserTime float64 `protobuf:"fixed64,1,opt,name=user_time,json=userTime,proto3" json:"user_time,omitempty"`
+	SysTime  float64 `protobuf:"fixed64,2,opt,name=sys_time,json=sysTime,proto3" json:"sys_time,omitempty"`
+	// maximum resident set size in KiB
+	MaxRss int64 `protobuf:"varint,3,opt,name=max_rss,json=maxRss,proto3" json:"max_rss,omitempty"`
+}
+
+func (x *ResourceUsage) Reset() {
+	*x = ResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceUsage) ProtoMessage() {}
+
+func (x *ResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceUsage.ProtoReflect.Descriptor instead.
+func (*ResourceUsage) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ResourceUsage) GetUserTime() float64 {
+	if x != nil {
+		return x.UserTime
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetSysTime() float64 {
+	if x != nil {
+		return x.SysTime
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetMaxRss() int64 {
+	if x != nil {
+		return x.MaxRss
+	}
+	return 0
+}
+
+// AuditEntry is a line of the audit log of the scoreboard server
+type AuditEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// server time, in nanoseconds since the unix epoch
+	TimeUnixNano int64 `protobuf:"varint,1,opt,name=time_unix_nano,json=timeUnixNano,proto3" json:"time_unix_nano,omitempty"`
+	// submit, or an admin action: list_boards, list_users, show, delete, reset,
+	// disqualify, requalify, note, render or reload_config
+	Action string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	// identity of the client, such as the uid of a unix socket peer
+	Peer     string `protobuf:"bytes,3,opt,name=peer,proto3" json:"peer,omitempty"`
+	Homework string `protobuf:"bytes,4,opt,name=homework,proto3" json:"homework,omitempty"`
+	User     string `protobuf:"bytes,5,opt,name=user,proto3" json:"user,omitempty"`
+	// created, updated, not_updated or refused for submissions, ok or refused
+	// for admin actions
+	Outcome string `protobuf:"bytes,6,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	// the reason of a refusal
+	Error  string            `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	Record *SubmissionRecord `protobuf:"bytes,8,opt,name=record,proto3" json:"record,omitempty"`
+	// the submitted results, so that the storage can be rebuilt from the log.
+	// Refused submissions are logged without their results, which may be
+	// arbitrarily large.
+	Results []*Result `protobuf:"bytes,9,rep,name=results,proto3" json:"results,omitempty"`
+	// the text of a note, or the reason of a disqualification
+	Note string `protobuf:"bytes,10,opt,name=note,proto3" json:"note,omitempty"`
+}
+
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEntry) ProtoMessage() {}
+
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AuditEntry) GetTimeUnixNano() int64 {
+	if x != nil {
+		return x.TimeUnixNano
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetHomework() string {
+	if x != nil {
+		return x.Homework
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetRecord() *SubmissionRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type ListBoardsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListBoardsRequest) Reset() {
+	*x = ListBoardsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBoardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBoardsRequest) ProtoMessage() {}
+
+func (x *ListBoardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBoardsRequest.ProtoReflect.Descriptor instead.
+func (*ListBoardsRequest) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{24}
+}
+
+type BoardList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Boards []*BoardSummary `protobuf:"bytes,1,rep,name=boards,proto3" json:"boards,omitempty"`
+}
+
+func (x *BoardList) Reset() {
+	*x = BoardList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BoardList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardList) ProtoMessage() {}
+
+func (x *BoardList) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardList.ProtoReflect.Descriptor instead.
+func (*BoardList) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *BoardList) GetBoards() []*BoardSummary {
+	if x != nil {
+		return x.Boards
+	}
+	return nil
+}
+
+type BoardSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Homework string `protobuf:"bytes,1,opt,name=homework,proto3" json:"homework,omitempty"`
+	Cases    int32  `protobuf:"varint,2,opt,name=cases,proto3" json:"cases,omitempty"`
+	Users    int32  `protobuf:"varint,3,opt,name=users,proto3" json:"users,omitempty"`
+}
+
+func (x *BoardSummary) Reset() {
+	*x = BoardSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BoardSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardSummary) ProtoMessage() {}
+
+func (x *BoardSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardSummary.ProtoReflect.Descriptor instead.
+func (*BoardSummary) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BoardSummary) GetHomework() string {
+	if x != nil {
+		return x.Homework
+	}
+	return ""
+}
+
+func (x *BoardSummary) GetCases() int32 {
+	if x != nil {
+		return x.Cases
+	}
+	return 0
+}
+
+func (x *BoardSummary) GetUsers() int32 {
+	if x != nil {
+		return x.Users
+	}
+	return 0
+}
+
+type ListUsersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Homework string `protobuf:"bytes,1,opt,name=homework,proto3" json:"homework,omitempty"`
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListUsersRequest) GetHomework() string {
+	if x != nil {
+		return x.Homework
+	}
+	return ""
+}
+
+type EntryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Homework string `protobuf:"bytes,1,opt,name=homework,proto3" json:"homework,omitempty"`
+	User     string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *EntryRequest) Reset() {
+	*x = EntryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntryRequest) ProtoMessage() {}
+
+func (x *EntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntryRequest.ProtoReflect.Descriptor instead.
+func (*EntryRequest) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *EntryRequest) GetHomework() string {
+	if x != nil {
+		return x.Homework
+	}
+	return ""
+}
+
+func (x *EntryRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+// Entry is the stored submission of a user, with its score and rank
+type Entry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Submission *StoredSubmission `protobuf:"bytes,1,opt,name=submission,proto3" json:"submission,omitempty"`
+	Score      *Score            `protobuf:"bytes,2,opt,name=score,proto3" json:"score,omitempty"`
+	// 0 for users who are not ranked
+	Rank int32 `protobuf:"varint,3,opt,name=rank,proto3" json:"rank,omitempty"`
+}
+
+func (x *Entry) Reset() {
+	*x = Entry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entry) ProtoMessage() {}
+
+func (x *Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entry.ProtoReflect.Descriptor instead.
+func (*Entry) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Entry) GetSubmission() *StoredSubmission {
+	if x != nil {
+		return x.Submission
+	}
+	return nil
+}
+
+func (x *Entry) GetScore() *Score {
+	if x != nil {
+		return x.Score
+	}
+	return nil
 }
 
-func (x *Result) GetCase() string {
+func (x *Entry) GetRank() int32 {
 	if x != nil {
-		return x.Case
+		return x.Rank
+	}
+	return 0
+}
+
+type DisqualifyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Homework string `protobuf:"bytes,1,opt,name=homework,proto3" json:"homework,omitempty"`
+	User     string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Reason   string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// lift the disqualification instead
+	Undo bool `protobuf:"varint,4,opt,name=undo,proto3" json:"undo,omitempty"`
+}
+
+func (x *DisqualifyRequest) Reset() {
+	*x = DisqualifyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisqualifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisqualifyRequest) ProtoMessage() {}
+
+func (x *DisqualifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisqualifyRequest.ProtoReflect.Descriptor instead.
+func (*DisqualifyRequest) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DisqualifyRequest) GetHomework() string {
+	if x != nil {
+		return x.Homework
 	}
 	return ""
 }
 
-func (x *Result) GetPassed() bool {
+func (x *DisqualifyRequest) GetUser() string {
 	if x != nil {
-		return x.Passed
+		return x.User
+	}
+	return ""
+}
+
+func (x *DisqualifyRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DisqualifyRequest) GetUndo() bool {
+	if x != nil {
+		return x.Undo
 	}
 	return false
 }
 
-func (x *Result) GetTime() float64 {
+type NoteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Homework string `protobuf:"bytes,1,opt,name=homework,proto3" json:"homework,omitempty"`
+	User     string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Text     string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *NoteRequest) Reset() {
+	*x = NoteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteRequest) ProtoMessage() {}
+
+func (x *NoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteRequest.ProtoReflect.Descriptor instead.
+func (*NoteRequest) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *NoteRequest) GetHomework() string {
 	if x != nil {
-		return x.Time
+		return x.Homework
 	}
-	return 0
+	return ""
 }
 
-func (x *Result) GetVerdict() string {
+func (x *NoteRequest) GetUser() string {
 	if x != nil {
-		return x.Verdict
+		return x.User
+	}
+	return ""
+}
+
+func (x *NoteRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type RenderRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// all the boards if empty
+	Homework string `protobuf:"bytes,1,opt,name=homework,proto3" json:"homework,omitempty"`
+}
+
+func (x *RenderRequest) Reset() {
+	*x = RenderRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderRequest) ProtoMessage() {}
+
+func (x *RenderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderRequest.ProtoReflect.Descriptor instead.
+func (*RenderRequest) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RenderRequest) GetHomework() string {
+	if x != nil {
+		return x.Homework
+	}
+	return ""
+}
+
+type ReloadConfigRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReloadConfigRequest) Reset() {
+	*x = ReloadConfigRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReloadConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadConfigRequest) ProtoMessage() {}
+
+func (x *ReloadConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadConfigRequest.ProtoReflect.Descriptor instead.
+func (*ReloadConfigRequest) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{33}
+}
+
+type AdminReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *AdminReply) Reset() {
+	*x = AdminReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scoreboard_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminReply) ProtoMessage() {}
+
+func (x *AdminReply) ProtoReflect() protoreflect.Message {
+	mi := &file_scoreboard_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminReply.ProtoReflect.Descriptor instead.
+func (*AdminReply) Descriptor() ([]byte, []int) {
+	return file_scoreboard_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AdminReply) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
@@ -470,7 +2562,19 @@ var file_scoreboard_proto_rawDesc = []byte{
 	0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x22, 0x2a, 0x0a, 0x14, 0x51, 0x75, 0x65, 0x72, 0x79, 0x48,
 	0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
 	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x22, 0xad, 0x01, 0x0a, 0x08, 0x48, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12,
+	0x6d, 0x65, 0x22, 0x47, 0x0a, 0x15, 0x51, 0x75, 0x65, 0x72, 0x79, 0x43, 0x61, 0x73, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x68,
+	0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68,
+	0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x75, 0x0a, 0x09, 0x43,
+	0x61, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x05, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x61, 0x73,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0xe4, 0x03, 0x0a, 0x08, 0x48, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12,
 	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
 	0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72,
@@ -481,32 +2585,270 @@ var file_scoreboard_proto_rawDesc = []byte{
 	0x61, 0x6c, 0x74, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52,
 	0x0b, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05,
 	0x63, 0x61, 0x73, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x61, 0x73,
-	0x65, 0x73, 0x22, 0x3c, 0x0a, 0x0a, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x46, 0x69, 0x6c, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
-	0x22, 0x2b, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x4c, 0x0a,
-	0x10, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
-	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x75,
-	0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x7a, 0x0a, 0x0e, 0x55,
-	0x73, 0x65, 0x72, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
-	0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65,
-	0x72, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x24, 0x0a,
-	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a,
-	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75,
-	0x6c, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x62, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x63, 0x61, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x12, 0x12, 0x0a,
-	0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x74, 0x69, 0x6d,
-	0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x32, 0x7c, 0x0a, 0x0a, 0x53,
+	0x65, 0x73, 0x12, 0x37, 0x0a, 0x0e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x5f, 0x63, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x52, 0x0d, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x12, 0x22, 0x0a, 0x06, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x62,
+	0x2e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x06, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12,
+	0x23, 0x0a, 0x0d, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x12, 0x49, 0x0a, 0x0f, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e,
+	0x70, 0x62, 0x2e, 0x48, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x52, 0x65, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0e, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x12,
+	0x25, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x1a, 0x41, 0x0a, 0x13, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xd4, 0x01, 0x0a, 0x06, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x5f,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x70, 0x75,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x70, 0x75,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x6e, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x6e, 0x46, 0x69, 0x6c, 0x65,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x61, 0x6e, 0x64, 0x62, 0x6f,
+	0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x61, 0x6e, 0x64, 0x62, 0x6f, 0x78,
+	0x22, 0x72, 0x0a, 0x0c, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
+	0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67,
+	0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x67, 0x67, 0x72, 0x65,
+	0x67, 0x61, 0x74, 0x65, 0x22, 0x3c, 0x0a, 0x0a, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x46, 0x69,
+	0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61,
+	0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61,
+	0x63, 0x6b, 0x22, 0x80, 0x01, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x2c, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x25,
+	0x0a, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0b, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x52, 0x07, 0x72, 0x65,
+	0x63, 0x65, 0x69, 0x70, 0x74, 0x22, 0xf6, 0x02, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0e, 0x74, 0x69,
+	0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f,
+	0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x64, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6f, 0x64, 0x65, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x36, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x52,
+	0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x09, 0x6f, 0x6c, 0x64, 0x5f,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x62,
+	0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x08, 0x6f, 0x6c, 0x64, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x12, 0x26, 0x0a, 0x09, 0x6e, 0x65, 0x77, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x08,
+	0x6e, 0x65, 0x77, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x34, 0x0a, 0x07, 0x4f, 0x75, 0x74, 0x63,
+	0x6f, 0x6d, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x0b, 0x0a, 0x07, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0f, 0x0a,
+	0x0b, 0x4e, 0x4f, 0x54, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x22, 0x68,
+	0x0a, 0x05, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x75, 0x6d, 0x5f, 0x70,
+	0x61, 0x73, 0x73, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6e, 0x75, 0x6d,
+	0x50, 0x61, 0x73, 0x73, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x70, 0x65, 0x6e,
+	0x61, 0x6c, 0x74, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x56, 0x0a, 0x07, 0x52, 0x65, 0x63, 0x65,
+	0x69, 0x70, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x6b, 0x65, 0x79,
+	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6b, 0x65, 0x79, 0x49, 0x64,
+	0x22, 0x2c, 0x0a, 0x0c, 0x48, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x4c, 0x69, 0x73, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x09, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x22, 0x45,
+	0x0a, 0x05, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77,
+	0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77,
+	0x6f, 0x72, 0x6b, 0x12, 0x20, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x6f, 0x77, 0x52,
+	0x04, 0x72, 0x6f, 0x77, 0x73, 0x22, 0xdd, 0x01, 0x0a, 0x08, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x52,
+	0x6f, 0x77, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x75,
+	0x6d, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
+	0x6e, 0x75, 0x6d, 0x50, 0x61, 0x73, 0x73, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x65, 0x6e, 0x61,
+	0x6c, 0x74, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b,
+	0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70,
+	0x62, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x64, 0x69, 0x73, 0x71, 0x75, 0x61, 0x6c,
+	0x69, 0x66, 0x69, 0x65, 0x64, 0x22, 0x52, 0x0a, 0x11, 0x57, 0x61, 0x74, 0x63, 0x68, 0x42, 0x6f,
+	0x61, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65,
+	0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65,
+	0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x8c, 0x01, 0x0a, 0x0a, 0x42, 0x6f,
+	0x61, 0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x27, 0x0a, 0x08, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e,
+	0x70, 0x62, 0x2e, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x48, 0x00, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42,
+	0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x54, 0x0a, 0x0b, 0x42, 0x6f, 0x61, 0x72,
+	0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6f, 0x61, 0x72, 0x64,
+	0x52, 0x6f, 0x77, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x55, 0x73, 0x65, 0x72, 0x73, 0x22, 0xc6,
+	0x01, 0x0a, 0x10, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x24, 0x0a,
+	0x0e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x4e,
+	0x61, 0x6e, 0x6f, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x66,
+	0x69, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x64, 0x69, 0x73, 0x71, 0x75,
+	0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x70, 0x62, 0x2e, 0x4e, 0x6f, 0x74, 0x65,
+	0x52, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x22, 0x54, 0x0a, 0x04, 0x4e, 0x6f, 0x74, 0x65, 0x12,
+	0x24, 0x0a, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e,
+	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69,
+	0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x65, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x97, 0x01,
+	0x0a, 0x0e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b,
+	0x12, 0x24, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f,
+	0x64, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63,
+	0x6f, 0x64, 0x65, 0x48, 0x61, 0x73, 0x68, 0x22, 0xc0, 0x03, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x63, 0x61, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x19,
+	0x0a, 0x08, 0x63, 0x70, 0x75, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x07, 0x63, 0x70, 0x75, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x6d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x62, 0x2e,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x6f, 0x64, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65,
+	0x12, 0x2d, 0x0a, 0x12, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x64, 0x5f, 0x64,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x64, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12,
+	0x34, 0x0a, 0x0c, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0b, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72,
+	0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x69, 0x6d, 0x69, 0x6e,
+	0x67, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x06, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x1a, 0x3a,
+	0x0a, 0x0c, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xaa, 0x01, 0x0a, 0x0b, 0x54,
+	0x69, 0x6d, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x75, 0x6e, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6d, 0x65,
+	0x61, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x06, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74,
+	0x64, 0x64, 0x65, 0x76, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x73, 0x74, 0x64, 0x64,
+	0x65, 0x76, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x03, 0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69,
+	0x76, 0x65, 0x5f, 0x63, 0x69, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x72, 0x65, 0x6c,
+	0x61, 0x74, 0x69, 0x76, 0x65, 0x43, 0x69, 0x22, 0x60, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x75, 0x73, 0x65,
+	0x72, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x79, 0x73, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x73, 0x79, 0x73, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x17, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x6d, 0x61, 0x78, 0x52, 0x73, 0x73, 0x22, 0xa6, 0x02, 0x0a, 0x0a, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x24, 0x0a, 0x0e, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x16,
+	0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x65, 0x65, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x75,
+	0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x75, 0x74,
+	0x63, 0x6f, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2c, 0x0a, 0x06, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x62, 0x2e,
+	0x53, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x52, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x24, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x62, 0x2e, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x6f, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x6f,
+	0x74, 0x65, 0x22, 0x13, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x35, 0x0a, 0x09, 0x42, 0x6f, 0x61, 0x72, 0x64,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x06, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x53,
+	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x06, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x22, 0x56,
+	0x0a, 0x0c, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1a,
+	0x0a, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x61,
+	0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x61, 0x73, 0x65, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x22, 0x2e, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x22, 0x3e, 0x0a, 0x0c, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f,
+	0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f,
+	0x72, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x72, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x34, 0x0a, 0x0a, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53,
+	0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52,
+	0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x22, 0x6f, 0x0a, 0x11, 0x44, 0x69,
+	0x73, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x64, 0x6f, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x75, 0x6e, 0x64, 0x6f, 0x22, 0x51, 0x0a, 0x0b, 0x4e,
+	0x6f, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x2b,
+	0x0a, 0x0d, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x22, 0x15, 0x0a, 0x13, 0x52,
+	0x65, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0x26, 0x0a, 0x0a, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0xf3, 0x01, 0x0a, 0x0a, 0x53,
 	0x63, 0x6f, 0x72, 0x65, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x12, 0x33, 0x0a, 0x06, 0x53, 0x75, 0x62,
 	0x6d, 0x69, 0x74, 0x12, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x75, 0x62,
 	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x1a, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x62,
@@ -514,8 +2856,46 @@ var file_scoreboard_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x48, 0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x12,
 	0x18, 0x2e, 0x70, 0x62, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x48, 0x6f, 0x6d, 0x65, 0x77, 0x6f,
 	0x72, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x62, 0x2e, 0x48,
-	0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x6f, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x0e, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x43, 0x61, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x70, 0x62,
+	0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x43, 0x61, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x61, 0x73, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0a, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x42, 0x6f, 0x61, 0x72, 0x64, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x42, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70,
+	0x62, 0x2e, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01,
+	0x32, 0xcc, 0x03, 0x0a, 0x05, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x34, 0x0a, 0x0a, 0x4c, 0x69,
+	0x73, 0x74, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00,
+	0x12, 0x2e, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x14, 0x2e,
+	0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x09, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6f, 0x61, 0x72, 0x64, 0x22, 0x00,
+	0x12, 0x29, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x2e, 0x70,
+	0x62, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x09,
+	0x2e, 0x70, 0x62, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x0b, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x2e, 0x70, 0x62, 0x2e,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70,
+	0x62, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x30,
+	0x0a, 0x0a, 0x52, 0x65, 0x73, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x2e, 0x70,
+	0x62, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e,
+	0x2e, 0x70, 0x62, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x35, 0x0a, 0x0a, 0x44, 0x69, 0x73, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x79, 0x12, 0x15,
+	0x2e, 0x70, 0x62, 0x2e, 0x44, 0x69, 0x73, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x2c, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x4e, 0x6f,
+	0x74, 0x65, 0x12, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x4e, 0x6f, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x2d, 0x0a, 0x06, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12,
+	0x11, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x0c, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e,
+	0x70, 0x62, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42,
+	0x1e, 0x5a, 0x1c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4e, 0x54,
+	0x48, 0x55, 0x2d, 0x6c, 0x73, 0x61, 0x6c, 0x61, 0x62, 0x2f, 0x73, 0x62, 0x2f, 0x70, 0x62, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -530,29 +2910,107 @@ func file_scoreboard_proto_rawDescGZIP() []byte {
 	return file_scoreboard_proto_rawDescData
 }
 
-var file_scoreboard_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_scoreboard_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_scoreboard_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_scoreboard_proto_goTypes = []interface{}{
-	(*QueryHomeworkRequest)(nil), // 0: pb.QueryHomeworkRequest
-	(*Homework)(nil),             // 1: pb.Homework
-	(*SourceFile)(nil),           // 2: pb.SourceFile
-	(*SubmissionReply)(nil),      // 3: pb.SubmissionReply
-	(*StoredSubmission)(nil),     // 4: pb.StoredSubmission
-	(*UserSubmission)(nil),       // 5: pb.UserSubmission
-	(*Result)(nil),               // 6: pb.Result
+	(SubmissionRecord_Outcome)(0), // 0: pb.SubmissionRecord.Outcome
+	(*QueryHomeworkRequest)(nil),  // 1: pb.QueryHomeworkRequest
+	(*QueryCaseTimesRequest)(nil), // 2: pb.QueryCaseTimesRequest
+	(*CaseTimes)(nil),             // 3: pb.CaseTimes
+	(*Homework)(nil),              // 4: pb.Homework
+	(*Limits)(nil),                // 5: pb.Limits
+	(*MetricColumn)(nil),          // 6: pb.MetricColumn
+	(*SourceFile)(nil),            // 7: pb.SourceFile
+	(*SubmissionReply)(nil),       // 8: pb.SubmissionReply
+	(*SubmissionRecord)(nil),      // 9: pb.SubmissionRecord
+	(*Score)(nil),                 // 10: pb.Score
+	(*Receipt)(nil),               // 11: pb.Receipt
+	(*HomeworkList)(nil),          // 12: pb.HomeworkList
+	(*Board)(nil),                 // 13: pb.Board
+	(*BoardRow)(nil),              // 14: pb.BoardRow
+	(*WatchBoardRequest)(nil),     // 15: pb.WatchBoardRequest
+	(*BoardEvent)(nil),            // 16: pb.BoardEvent
+	(*BoardUpdate)(nil),           // 17: pb.BoardUpdate
+	(*StoredSubmission)(nil),      // 18: pb.StoredSubmission
+	(*Note)(nil),                  // 19: pb.Note
+	(*UserSubmission)(nil),        // 20: pb.UserSubmission
+	(*Result)(nil),                // 21: pb.Result
+	(*TimingStats)(nil),           // 22: pb.TimingStats
+	(*ResourceUsage)(nil),         // 23: pb.ResourceUsage
+	(*AuditEntry)(nil),            // 24: pb.AuditEntry
+	(*ListBoardsRequest)(nil),     // 25: pb.ListBoardsRequest
+	(*BoardList)(nil),             // 26: pb.BoardList
+	(*BoardSummary)(nil),          // 27: pb.BoardSummary
+	(*ListUsersRequest)(nil),      // 28: pb.ListUsersRequest
+	(*EntryRequest)(nil),          // 29: pb.EntryRequest
+	(*Entry)(nil),                 // 30: pb.Entry
+	(*DisqualifyRequest)(nil),     // 31: pb.DisqualifyRequest
+	(*NoteRequest)(nil),           // 32: pb.NoteRequest
+	(*RenderRequest)(nil),         // 33: pb.RenderRequest
+	(*ReloadConfigRequest)(nil),   // 34: pb.ReloadConfigRequest
+	(*AdminReply)(nil),            // 35: pb.AdminReply
+	nil,                           // 36: pb.CaseTimes.TimesEntry
+	nil,                           // 37: pb.Homework.ReferenceTimesEntry
+	nil,                           // 38: pb.Result.MetricsEntry
 }
 var file_scoreboard_proto_depIdxs = []int32{
-	2, // 0: pb.Homework.files:type_name -> pb.SourceFile
-	6, // 1: pb.StoredSubmission.results:type_name -> pb.Result
-	6, // 2: pb.UserSubmission.results:type_name -> pb.Result
-	5, // 3: pb.Scoreboard.Submit:input_type -> pb.UserSubmission
-	0, // 4: pb.Scoreboard.QueryHomework:input_type -> pb.QueryHomeworkRequest
-	3, // 5: pb.Scoreboard.Submit:output_type -> pb.SubmissionReply
-	1, // 6: pb.Scoreboard.QueryHomework:output_type -> pb.Homework
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	36, // 0: pb.CaseTimes.times:type_name -> pb.CaseTimes.TimesEntry
+	7,  // 1: pb.Homework.files:type_name -> pb.SourceFile
+	6,  // 2: pb.Homework.metric_columns:type_name -> pb.MetricColumn
+	5,  // 3: pb.Homework.limits:type_name -> pb.Limits
+	37, // 4: pb.Homework.reference_times:type_name -> pb.Homework.ReferenceTimesEntry
+	9,  // 5: pb.SubmissionReply.record:type_name -> pb.SubmissionRecord
+	11, // 6: pb.SubmissionReply.receipt:type_name -> pb.Receipt
+	0,  // 7: pb.SubmissionRecord.outcome:type_name -> pb.SubmissionRecord.Outcome
+	10, // 8: pb.SubmissionRecord.old_score:type_name -> pb.Score
+	10, // 9: pb.SubmissionRecord.new_score:type_name -> pb.Score
+	14, // 10: pb.Board.rows:type_name -> pb.BoardRow
+	21, // 11: pb.BoardRow.results:type_name -> pb.Result
+	13, // 12: pb.BoardEvent.snapshot:type_name -> pb.Board
+	17, // 13: pb.BoardEvent.update:type_name -> pb.BoardUpdate
+	14, // 14: pb.BoardUpdate.rows:type_name -> pb.BoardRow
+	21, // 15: pb.StoredSubmission.results:type_name -> pb.Result
+	19, // 16: pb.StoredSubmission.notes:type_name -> pb.Note
+	21, // 17: pb.UserSubmission.results:type_name -> pb.Result
+	38, // 18: pb.Result.metrics:type_name -> pb.Result.MetricsEntry
+	23, // 19: pb.Result.runner_usage:type_name -> pb.ResourceUsage
+	22, // 20: pb.Result.timing:type_name -> pb.TimingStats
+	9,  // 21: pb.AuditEntry.record:type_name -> pb.SubmissionRecord
+	21, // 22: pb.AuditEntry.results:type_name -> pb.Result
+	27, // 23: pb.BoardList.boards:type_name -> pb.BoardSummary
+	18, // 24: pb.Entry.submission:type_name -> pb.StoredSubmission
+	10, // 25: pb.Entry.score:type_name -> pb.Score
+	20, // 26: pb.Scoreboard.Submit:input_type -> pb.UserSubmission
+	1,  // 27: pb.Scoreboard.QueryHomework:input_type -> pb.QueryHomeworkRequest
+	2,  // 28: pb.Scoreboard.QueryCaseTimes:input_type -> pb.QueryCaseTimesRequest
+	15, // 29: pb.Scoreboard.WatchBoard:input_type -> pb.WatchBoardRequest
+	25, // 30: pb.Admin.ListBoards:input_type -> pb.ListBoardsRequest
+	28, // 31: pb.Admin.ListUsers:input_type -> pb.ListUsersRequest
+	29, // 32: pb.Admin.GetEntry:input_type -> pb.EntryRequest
+	29, // 33: pb.Admin.DeleteEntry:input_type -> pb.EntryRequest
+	29, // 34: pb.Admin.ResetEntry:input_type -> pb.EntryRequest
+	31, // 35: pb.Admin.Disqualify:input_type -> pb.DisqualifyRequest
+	32, // 36: pb.Admin.AddNote:input_type -> pb.NoteRequest
+	33, // 37: pb.Admin.Render:input_type -> pb.RenderRequest
+	34, // 38: pb.Admin.ReloadConfig:input_type -> pb.ReloadConfigRequest
+	8,  // 39: pb.Scoreboard.Submit:output_type -> pb.SubmissionReply
+	4,  // 40: pb.Scoreboard.QueryHomework:output_type -> pb.Homework
+	3,  // 41: pb.Scoreboard.QueryCaseTimes:output_type -> pb.CaseTimes
+	16, // 42: pb.Scoreboard.WatchBoard:output_type -> pb.BoardEvent
+	26, // 43: pb.Admin.ListBoards:output_type -> pb.BoardList
+	13, // 44: pb.Admin.ListUsers:output_type -> pb.Board
+	30, // 45: pb.Admin.GetEntry:output_type -> pb.Entry
+	35, // 46: pb.Admin.DeleteEntry:output_type -> pb.AdminReply
+	35, // 47: pb.Admin.ResetEntry:output_type -> pb.AdminReply
+	35, // 48: pb.Admin.Disqualify:output_type -> pb.AdminReply
+	35, // 49: pb.Admin.AddNote:output_type -> pb.AdminReply
+	35, // 50: pb.Admin.Render:output_type -> pb.AdminReply
+	35, // 51: pb.Admin.ReloadConfig:output_type -> pb.AdminReply
+	39, // [39:52] is the sub-list for method output_type
+	26, // [26:39] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_scoreboard_proto_init() }
@@ -573,8 +3031,80 @@ func file_scoreboard_proto_init() {
 				return nil
 			}
 		}
-		file_scoreboard_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Homework); i {
+		file_scoreboard_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryCaseTimesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CaseTimes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Homework); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Limits); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetricColumn); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SourceFile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmissionReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -585,8 +3115,8 @@ func file_scoreboard_proto_init() {
 				return nil
 			}
 		}
-		file_scoreboard_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SourceFile); i {
+		file_scoreboard_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmissionRecord); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -597,8 +3127,8 @@ func file_scoreboard_proto_init() {
 				return nil
 			}
 		}
-		file_scoreboard_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubmissionReply); i {
+		file_scoreboard_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Score); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -609,7 +3139,91 @@ func file_scoreboard_proto_init() {
 				return nil
 			}
 		}
-		file_scoreboard_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		file_scoreboard_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Receipt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HomeworkList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Board); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BoardRow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchBoardRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BoardEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BoardUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*StoredSubmission); i {
 			case 0:
 				return &v.state
@@ -621,7 +3235,19 @@ func file_scoreboard_proto_init() {
 				return nil
 			}
 		}
-		file_scoreboard_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_scoreboard_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Note); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UserSubmission); i {
 			case 0:
 				return &v.state
@@ -633,7 +3259,7 @@ func file_scoreboard_proto_init() {
 				return nil
 			}
 		}
-		file_scoreboard_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_scoreboard_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Result); i {
 			case 0:
 				return &v.state
@@ -645,19 +3271,192 @@ func file_scoreboard_proto_init() {
 				return nil
 			}
 		}
+		file_scoreboard_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TimingStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBoardsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BoardList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BoardSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListUsersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EntryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Entry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisqualifyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NoteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenderRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReloadConfigRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scoreboard_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_scoreboard_proto_msgTypes[15].OneofWrappers = []interface{}{
+		(*BoardEvent_Snapshot)(nil),
+		(*BoardEvent_Update)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_scoreboard_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      1,
+			NumMessages:   38,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_scoreboard_proto_goTypes,
 		DependencyIndexes: file_scoreboard_proto_depIdxs,
+		EnumInfos:         file_scoreboard_proto_enumTypes,
 		MessageInfos:      file_scoreboard_proto_msgTypes,
 	}.Build()
 	File_scoreboard_proto = out.File
@@ -680,6 +3479,10 @@ const _ = grpc.SupportPackageIsVersion6
 type ScoreboardClient interface {
 	Submit(ctx context.Context, in *UserSubmission, opts ...grpc.CallOption) (*SubmissionReply, error)
 	QueryHomework(ctx context.Context, in *QueryHomeworkRequest, opts ...grpc.CallOption) (*Homework, error)
+	QueryCaseTimes(ctx context.Context, in *QueryCaseTimesRequest, opts ...grpc.CallOption) (*CaseTimes, error)
+	// WatchBoard streams a snapshot of the board, then the rows that changed
+	// after each submission
+	WatchBoard(ctx context.Context, in *WatchBoardRequest, opts ...grpc.CallOption) (Scoreboard_WatchBoardClient, error)
 }
 
 type scoreboardClient struct {
@@ -696,22 +3499,67 @@ func (c *scoreboardClient) Submit(ctx context.Context, in *UserSubmission, opts
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *scoreboardClient) QueryHomework(ctx context.Context, in *QueryHomeworkRequest, opts ...grpc.CallOption) (*Homework, error) {
+	out := new(Homework)
+	err := c.cc.Invoke(ctx, "/pb.Scoreboard/QueryHomework", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scoreboardClient) QueryCaseTimes(ctx context.Context, in *QueryCaseTimesRequest, opts ...grpc.CallOption) (*CaseTimes, error) {
+	out := new(CaseTimes)
+	err := c.cc.Invoke(ctx, "/pb.Scoreboard/QueryCaseTimes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scoreboardClient) WatchBoard(ctx context.Context, in *WatchBoardRequest, opts ...grpc.CallOption) (Scoreboard_WatchBoardClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Scoreboard_serviceDesc.Streams[0], "/pb.Scoreboard/WatchBoard", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scoreboardWatchBoardClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (c *scoreboardClient) QueryHomework(ctx context.Context, in *QueryHomeworkRequest, opts ...grpc.CallOption) (*Homework, error) {
-	out := new(Homework)
-	err := c.cc.Invoke(ctx, "/pb.Scoreboard/QueryHomework", in, out, opts...)
-	if err != nil {
+type Scoreboard_WatchBoardClient interface {
+	Recv() (*BoardEvent, error)
+	grpc.ClientStream
+}
+
+type scoreboardWatchBoardClient struct {
+	grpc.ClientStream
+}
+
+func (x *scoreboardWatchBoardClient) Recv() (*BoardEvent, error) {
+	m := new(BoardEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
 // ScoreboardServer is the server API for Scoreboard service.
 type ScoreboardServer interface {
 	Submit(context.Context, *UserSubmission) (*SubmissionReply, error)
 	QueryHomework(context.Context, *QueryHomeworkRequest) (*Homework, error)
+	QueryCaseTimes(context.Context, *QueryCaseTimesRequest) (*CaseTimes, error)
+	// WatchBoard streams a snapshot of the board, then the rows that changed
+	// after each submission
+	WatchBoard(*WatchBoardRequest, Scoreboard_WatchBoardServer) error
 }
 
 // UnimplementedScoreboardServer can be embedded to have forward compatible implementations.
@@ -724,6 +3572,12 @@ func (*UnimplementedScoreboardServer) Submit(context.Context, *UserSubmission) (
 func (*UnimplementedScoreboardServer) QueryHomework(context.Context, *QueryHomeworkRequest) (*Homework, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryHomework not implemented")
 }
+func (*UnimplementedScoreboardServer) QueryCaseTimes(context.Context, *QueryCaseTimesRequest) (*CaseTimes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryCaseTimes not implemented")
+}
+func (*UnimplementedScoreboardServer) WatchBoard(*WatchBoardRequest, Scoreboard_WatchBoardServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBoard not implemented")
+}
 
 func RegisterScoreboardServer(s *grpc.Server, srv ScoreboardServer) {
 	s.RegisterService(&_Scoreboard_serviceDesc, srv)
@@ -765,6 +3619,45 @@ func _Scoreboard_QueryHomework_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Scoreboard_QueryCaseTimes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryCaseTimesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScoreboardServer).QueryCaseTimes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scoreboard/QueryCaseTimes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScoreboardServer).QueryCaseTimes(ctx, req.(*QueryCaseTimesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scoreboard_WatchBoard_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBoardRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScoreboardServer).WatchBoard(m, &scoreboardWatchBoardServer{stream})
+}
+
+type Scoreboard_WatchBoardServer interface {
+	Send(*BoardEvent) error
+	grpc.ServerStream
+}
+
+type scoreboardWatchBoardServer struct {
+	grpc.ServerStream
+}
+
+func (x *scoreboardWatchBoardServer) Send(m *BoardEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Scoreboard_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.Scoreboard",
 	HandlerType: (*ScoreboardServer)(nil),
@@ -777,6 +3670,384 @@ var _Scoreboard_serviceDesc = grpc.ServiceDesc{
 			MethodName: "QueryHomework",
 			Handler:    _Scoreboard_QueryHomework_Handler,
 		},
+		{
+			MethodName: "QueryCaseTimes",
+			Handler:    _Scoreboard_QueryCaseTimes_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchBoard",
+			Handler:       _Scoreboard_WatchBoard_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "scoreboard.proto",
+}
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AdminClient interface {
+	ListBoards(ctx context.Context, in *ListBoardsRequest, opts ...grpc.CallOption) (*BoardList, error)
+	// ListUsers returns the board of the homework, with the disqualified users
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*Board, error)
+	GetEntry(ctx context.Context, in *EntryRequest, opts ...grpc.CallOption) (*Entry, error)
+	DeleteEntry(ctx context.Context, in *EntryRequest, opts ...grpc.CallOption) (*AdminReply, error)
+	// ResetEntry removes the results of the entry, keeping its notes
+	ResetEntry(ctx context.Context, in *EntryRequest, opts ...grpc.CallOption) (*AdminReply, error)
+	Disqualify(ctx context.Context, in *DisqualifyRequest, opts ...grpc.CallOption) (*AdminReply, error)
+	AddNote(ctx context.Context, in *NoteRequest, opts ...grpc.CallOption) (*AdminReply, error)
+	Render(ctx context.Context, in *RenderRequest, opts ...grpc.CallOption) (*AdminReply, error)
+	// ReloadConfig loads the homeworks again, rescoring the boards of changed
+	// homeworks, adding new homeworks and dropping removed ones
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*AdminReply, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) ListBoards(ctx context.Context, in *ListBoardsRequest, opts ...grpc.CallOption) (*BoardList, error) {
+	out := new(BoardList)
+	err := c.cc.Invoke(ctx, "/pb.Admin/ListBoards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*Board, error) {
+	out := new(Board)
+	err := c.cc.Invoke(ctx, "/pb.Admin/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetEntry(ctx context.Context, in *EntryRequest, opts ...grpc.CallOption) (*Entry, error) {
+	out := new(Entry)
+	err := c.cc.Invoke(ctx, "/pb.Admin/GetEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeleteEntry(ctx context.Context, in *EntryRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/pb.Admin/DeleteEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ResetEntry(ctx context.Context, in *EntryRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/pb.Admin/ResetEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Disqualify(ctx context.Context, in *DisqualifyRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/pb.Admin/Disqualify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AddNote(ctx context.Context, in *NoteRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/pb.Admin/AddNote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Render(ctx context.Context, in *RenderRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/pb.Admin/Render", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/pb.Admin/ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+type AdminServer interface {
+	ListBoards(context.Context, *ListBoardsRequest) (*BoardList, error)
+	// ListUsers returns the board of the homework, with the disqualified users
+	ListUsers(context.Context, *ListUsersRequest) (*Board, error)
+	GetEntry(context.Context, *EntryRequest) (*Entry, error)
+	DeleteEntry(context.Context, *EntryRequest) (*AdminReply, error)
+	// ResetEntry removes the results of the entry, keeping its notes
+	ResetEntry(context.Context, *EntryRequest) (*AdminReply, error)
+	Disqualify(context.Context, *DisqualifyRequest) (*AdminReply, error)
+	AddNote(context.Context, *NoteRequest) (*AdminReply, error)
+	Render(context.Context, *RenderRequest) (*AdminReply, error)
+	// ReloadConfig loads the homeworks again, rescoring the boards of changed
+	// homeworks, adding new homeworks and dropping removed ones
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*AdminReply, error)
+}
+
+// UnimplementedAdminServer can be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (*UnimplementedAdminServer) ListBoards(context.Context, *ListBoardsRequest) (*BoardList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBoards not implemented")
+}
+func (*UnimplementedAdminServer) ListUsers(context.Context, *ListUsersRequest) (*Board, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (*UnimplementedAdminServer) GetEntry(context.Context, *EntryRequest) (*Entry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEntry not implemented")
+}
+func (*UnimplementedAdminServer) DeleteEntry(context.Context, *EntryRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEntry not implemented")
+}
+func (*UnimplementedAdminServer) ResetEntry(context.Context, *EntryRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetEntry not implemented")
+}
+func (*UnimplementedAdminServer) Disqualify(context.Context, *DisqualifyRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Disqualify not implemented")
+}
+func (*UnimplementedAdminServer) AddNote(context.Context, *NoteRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNote not implemented")
+}
+func (*UnimplementedAdminServer) Render(context.Context, *RenderRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Render not implemented")
+}
+func (*UnimplementedAdminServer) ReloadConfig(context.Context, *ReloadConfigRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
+}
+
+func _Admin_ListBoards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBoardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListBoards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Admin/ListBoards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListBoards(ctx, req.(*ListBoardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Admin/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Admin/GetEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetEntry(ctx, req.(*EntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeleteEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeleteEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Admin/DeleteEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeleteEntry(ctx, req.(*EntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ResetEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ResetEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Admin/ResetEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ResetEntry(ctx, req.(*EntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Disqualify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisqualifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Disqualify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Admin/Disqualify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Disqualify(ctx, req.(*DisqualifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AddNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AddNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Admin/AddNote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AddNote(ctx, req.(*NoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Render_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Render(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Admin/Render",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Render(ctx, req.(*RenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Admin/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBoards",
+			Handler:    _Admin_ListBoards_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _Admin_ListUsers_Handler,
+		},
+		{
+			MethodName: "GetEntry",
+			Handler:    _Admin_GetEntry_Handler,
+		},
+		{
+			MethodName: "DeleteEntry",
+			Handler:    _Admin_DeleteEntry_Handler,
+		},
+		{
+			MethodName: "ResetEntry",
+			Handler:    _Admin_ResetEntry_Handler,
+		},
+		{
+			MethodName: "Disqualify",
+			Handler:    _Admin_Disqualify_Handler,
+		},
+		{
+			MethodName: "AddNote",
+			Handler:    _Admin_AddNote_Handler,
+		},
+		{
+			MethodName: "Render",
+			Handler:    _Admin_Render_Handler,
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _Admin_ReloadConfig_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "scoreboard.proto",
@@ -0,0 +1,441 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Audit log outcome of the admin actions that succeeded
+const outcomeOK = "ok"
+
+// adminServer implements the Admin service on the boards of a Server
+type adminServer struct {
+	*Server
+}
+
+var _ pb.AdminServer = adminServer{}
+
+// Admin returns the Admin service of the server. It is only available to root,
+// the user running the server and Options.AdminUIDs, connected over a unix
+// socket served with PeerCredentials. Every call is recorded in the audit log.
+func (s *Server) Admin() pb.AdminServer {
+	return adminServer{s}
+}
+
+// isAdmin tells whether the peer may call the Admin service
+func (s *Server) isAdmin(info PeerInfo) bool {
+	if !info.Unix {
+		return false
+	}
+	if info.UID == 0 || info.UID == uint32(os.Getuid()) {
+		return true
+	}
+	for _, uid := range s.adminUIDs {
+		if info.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) authorizeAdmin(ctx context.Context) error {
+	info := peerInfo(ctx)
+	if !info.Unix {
+		return status.Error(codes.PermissionDenied, "the admin service is only available on the unix socket")
+	}
+	if !s.isAdmin(info) {
+		return status.Errorf(codes.PermissionDenied, "uid %d is not an admin of the scoreboard", info.UID)
+	}
+	return nil
+}
+
+// recordAdmin records the outcome of the admin action in the audit log
+func (s *Server) recordAdmin(entry *pb.AuditEntry, err error) {
+	if err != nil {
+		entry.Outcome = outcomeRefused
+		entry.Error = err.Error()
+	} else {
+		entry.Outcome = outcomeOK
+	}
+	s.audit(entry)
+}
+
+// begin authorizes the admin action of the entry, filling in its time and
+// peer. Refused actions are recorded.
+func (a adminServer) begin(ctx context.Context, entry *pb.AuditEntry) error {
+	entry.TimeUnixNano = a.clock.Now().UnixNano()
+	entry.Peer = peerIdentity(ctx)
+	err := a.authorizeAdmin(ctx)
+	if err != nil {
+		a.finish(entry, "", err)
+	}
+	return err
+}
+
+// finish records and logs the outcome of the admin action
+func (a adminServer) finish(entry *pb.AuditEntry, msg string, err error) {
+	a.recordAdmin(entry, err)
+	a.logAdmin(entry, msg, err)
+}
+
+func (a adminServer) logAdmin(entry *pb.AuditEntry, msg string, err error) {
+	target := strings.Trim(entry.Homework+"/"+entry.User, "/")
+	if target != "" {
+		target = " " + target
+	}
+	if err != nil {
+		a.logger.Printf("Refused admin %s%s from %s: %v", entry.Action, target, entry.Peer, err)
+	} else {
+		a.logger.Printf("Admin %s%s by %s: %s", entry.Action, target, entry.Peer, msg)
+	}
+}
+
+// board returns the board of the homework of the entry
+func (a adminServer) board(entry *pb.AuditEntry) (*Board, error) {
+	b, ok := a.Board(entry.Homework)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no such homework %q", entry.Homework)
+	}
+	return b, nil
+}
+
+func (a adminServer) ListBoards(ctx context.Context, req *pb.ListBoardsRequest) (*pb.BoardList, error) {
+	entry := &pb.AuditEntry{Action: "list_boards"}
+	if err := a.begin(ctx, entry); err != nil {
+		return nil, err
+	}
+	list := &pb.BoardList{}
+	for _, name := range a.boardNames() {
+		if b, ok := a.Board(name); ok {
+			list.Boards = append(list.Boards, b.summary())
+		}
+	}
+	a.finish(entry, fmt.Sprintf("%d boards", len(list.Boards)), nil)
+	return list, nil
+}
+
+func (a adminServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.Board, error) {
+	entry := &pb.AuditEntry{Action: "list_users", Homework: req.Homework}
+	if err := a.begin(ctx, entry); err != nil {
+		return nil, err
+	}
+	b, err := a.board(entry)
+	if err != nil {
+		a.finish(entry, "", err)
+		return nil, err
+	}
+	board := b.Snapshot()
+	a.finish(entry, fmt.Sprintf("%d users", len(board.Rows)), nil)
+	return board, nil
+}
+
+func (a adminServer) GetEntry(ctx context.Context, req *pb.EntryRequest) (*pb.Entry, error) {
+	entry := &pb.AuditEntry{Action: "show", Homework: req.Homework, User: req.User}
+	if err := a.begin(ctx, entry); err != nil {
+		return nil, err
+	}
+	b, err := a.board(entry)
+	if err != nil {
+		a.finish(entry, "", err)
+		return nil, err
+	}
+	e := b.entry(req.User)
+	if e == nil {
+		err = status.Errorf(codes.NotFound, "%s has no entry in %s", req.User, req.Homework)
+		a.finish(entry, "", err)
+		return nil, err
+	}
+	a.finish(entry, "shown", nil)
+	return e, nil
+}
+
+// update applies the admin action of the entry to the board
+func (a adminServer) update(ctx context.Context, entry *pb.AuditEntry) (*pb.AdminReply, error) {
+	if err := a.begin(ctx, entry); err != nil {
+		return nil, err
+	}
+	b, err := a.board(entry)
+	if err != nil {
+		a.finish(entry, "", err)
+		return nil, err
+	}
+	msg, err := b.adminUpdate(entry, adminChange(entry))
+	a.logAdmin(entry, msg, err)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AdminReply{Message: msg}, nil
+}
+
+func (a adminServer) DeleteEntry(ctx context.Context, req *pb.EntryRequest) (*pb.AdminReply, error) {
+	return a.update(ctx, &pb.AuditEntry{Action: "delete", Homework: req.Homework, User: req.User})
+}
+
+func (a adminServer) ResetEntry(ctx context.Context, req *pb.EntryRequest) (*pb.AdminReply, error) {
+	return a.update(ctx, &pb.AuditEntry{Action: "reset", Homework: req.Homework, User: req.User})
+}
+
+func (a adminServer) Disqualify(ctx context.Context, req *pb.DisqualifyRequest) (*pb.AdminReply, error) {
+	action := "disqualify"
+	if req.Undo {
+		action = "requalify"
+	}
+	return a.update(ctx, &pb.AuditEntry{Action: action, Homework: req.Homework, User: req.User, Note: req.Reason})
+}
+
+func (a adminServer) AddNote(ctx context.Context, req *pb.NoteRequest) (*pb.AdminReply, error) {
+	return a.update(ctx, &pb.AuditEntry{Action: "note", Homework: req.Homework, User: req.User, Note: req.Text})
+}
+
+func (a adminServer) Render(ctx context.Context, req *pb.RenderRequest) (*pb.AdminReply, error) {
+	entry := &pb.AuditEntry{Action: "render", Homework: req.Homework}
+	if err := a.begin(ctx, entry); err != nil {
+		return nil, err
+	}
+	names := []string{req.Homework}
+	if req.Homework == "" {
+		names = a.boardNames()
+	}
+	var rendered []string
+	var err error
+	for _, name := range names {
+		b, ok := a.Board(name)
+		if !ok {
+			err = status.Errorf(codes.NotFound, "no such homework %q", name)
+			break
+		}
+		if renderErr := b.render(); renderErr != nil {
+			err = status.Errorf(codes.Internal, "failed to render %s: %v", name, renderErr)
+			break
+		}
+		rendered = append(rendered, name)
+	}
+	msg := fmt.Sprintf("rendered [%s]", strings.Join(rendered, " "))
+	a.finish(entry, msg, err)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AdminReply{Message: msg}, nil
+}
+
+func (a adminServer) ReloadConfig(ctx context.Context, req *pb.ReloadConfigRequest) (*pb.AdminReply, error) {
+	entry := &pb.AuditEntry{Action: "reload_config"}
+	if err := a.begin(ctx, entry); err != nil {
+		return nil, err
+	}
+	msg, err := a.reloadConfig()
+	a.finish(entry, msg, err)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AdminReply{Message: msg}, nil
+}
+
+// reloadConfig loads the homeworks again. The boards of changed homeworks are
+// rescored, new homeworks are loaded from the storage, and the boards of
+// removed homeworks are dropped, keeping their storage.
+func (s *Server) reloadConfig() (string, error) {
+	homeworks, err := s.config.Homeworks()
+	if err != nil {
+		return "", status.Errorf(codes.FailedPrecondition, "failed to load the config: %v", err)
+	}
+	s.boardsLock.Lock()
+	defer s.boardsLock.Unlock()
+	// load the new homeworks first, so that nothing changes if one fails
+	boards := make(map[string]*Board)
+	var added, changed, removed []string
+	for _, hw := range homeworks {
+		if _, ok := s.boards[hw.Name]; ok {
+			continue
+		}
+		b, err := s.loadBoard(hw)
+		if err != nil {
+			return "", status.Errorf(codes.Internal, "could not load homework %s: %v", hw.Name, err)
+		}
+		boards[hw.Name] = b
+		added = append(added, hw.Name)
+	}
+	for _, hw := range homeworks {
+		if b, ok := s.boards[hw.Name]; ok {
+			if b.setHomework(hw) {
+				changed = append(changed, hw.Name)
+			}
+			boards[hw.Name] = b
+		}
+	}
+	for name := range s.boards {
+		if _, ok := boards[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	s.boards = boards
+	sort.Strings(added)
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return fmt.Sprintf("%d homeworks, added [%s], changed [%s], removed [%s]", len(boards),
+		strings.Join(added, " "), strings.Join(changed, " "), strings.Join(removed, " ")), nil
+}
+
+// adminChange returns how the admin action of the entry changes the stored
+// submission of the user, or nil if the action does not change the board.
+// The change is given a copy of the stored submission, nil if there is none,
+// and returns the new one, nil to delete the entry.
+func adminChange(entry *pb.AuditEntry) func(old *pb.StoredSubmission) (*pb.StoredSubmission, string, error) {
+	notFound := func() error {
+		return status.Errorf(codes.NotFound, "%s has no entry in %s", entry.User, entry.Homework)
+	}
+	note := func(text string) *pb.Note {
+		if entry.Note != "" {
+			text += ": " + entry.Note
+		}
+		return &pb.Note{TimeUnixNano: entry.TimeUnixNano, Peer: entry.Peer, Text: text}
+	}
+	switch entry.Action {
+	case "delete":
+		return func(old *pb.StoredSubmission) (*pb.StoredSubmission, string, error) {
+			if old == nil {
+				return nil, "", notFound()
+			}
+			return nil, fmt.Sprintf("deleted the entry of %s", entry.User), nil
+		}
+	case "reset":
+		return func(old *pb.StoredSubmission) (*pb.StoredSubmission, string, error) {
+			if old == nil {
+				return nil, "", notFound()
+			}
+			return &pb.StoredSubmission{User: old.User, Disqualified: old.Disqualified, Notes: old.Notes},
+				fmt.Sprintf("reset the entry of %s", entry.User), nil
+		}
+	case "disqualify":
+		return func(old *pb.StoredSubmission) (*pb.StoredSubmission, string, error) {
+			if old == nil {
+				// the entry is kept, so that later submissions stay disqualified
+				if !validUser(entry.User) {
+					return nil, "", status.Errorf(codes.InvalidArgument, "invalid user name %q", entry.User)
+				}
+				old = &pb.StoredSubmission{User: entry.User}
+			}
+			old.Disqualified = true
+			old.Notes = append(old.Notes, note("disqualified"))
+			return old, fmt.Sprintf("disqualified %s", entry.User), nil
+		}
+	case "requalify":
+		return func(old *pb.StoredSubmission) (*pb.StoredSubmission, string, error) {
+			if old == nil {
+				return nil, "", notFound()
+			}
+			old.Disqualified = false
+			old.Notes = append(old.Notes, note("disqualification lifted"))
+			return old, fmt.Sprintf("lifted the disqualification of %s", entry.User), nil
+		}
+	case "note":
+		return func(old *pb.StoredSubmission) (*pb.StoredSubmission, string, error) {
+			if entry.Note == "" {
+				return nil, "", status.Error(codes.InvalidArgument, "empty note")
+			}
+			if old == nil {
+				return nil, "", notFound()
+			}
+			old.Notes = append(old.Notes, &pb.Note{TimeUnixNano: entry.TimeUnixNano, Peer: entry.Peer, Text: entry.Note})
+			return old, fmt.Sprintf("added a note to the entry of %s", entry.User), nil
+		}
+	}
+	return nil
+}
+
+// adminUpdate applies the change of the admin action to the entry of the user
+// and records the action in the audit log, while holding the lock like
+// updateSubmission
+func (b *Board) adminUpdate(entry *pb.AuditEntry, change func(old *pb.StoredSubmission) (*pb.StoredSubmission, string, error)) (string, error) {
+	b.submissionLock.Lock()
+	defer b.submissionLock.Unlock()
+	var old *pb.StoredSubmission
+	if e, ok := b.submissions[entry.User]; ok {
+		old = proto.Clone(e.Submission).(*pb.StoredSubmission)
+	}
+	new, msg, err := change(old)
+	if err == nil {
+		var storeErr error
+		if new == nil {
+			delete(b.submissions, entry.User)
+			storeErr = b.server.storage.Delete(b.Homework.Name, entry.User)
+		} else {
+			b.submissions[entry.User] = BoardEntry{
+				Score:      calcScore(b.Homework, new.Results),
+				Submission: new,
+			}
+			storeErr = b.server.storage.Store(b.Homework.Name, new)
+		}
+		if storeErr != nil {
+			b.server.logger.Printf("Failed to store submission %s/%s: %v", b.Homework.Name, entry.User, storeErr)
+		}
+		b.renderBoard()
+		b.publish()
+	}
+	b.server.recordAdmin(entry, err)
+	return msg, err
+}
+
+// setHomework replaces the homework of the board and rescores the entries,
+// returning false if the homework did not change
+func (b *Board) setHomework(hw *pb.Homework) bool {
+	b.submissionLock.Lock()
+	defer b.submissionLock.Unlock()
+	if proto.Equal(b.Homework, hw) {
+		return false
+	}
+	b.Homework = hw
+	for user, entry := range b.submissions {
+		entry.Score = calcScore(hw, entry.Submission.Results)
+		b.submissions[user] = entry
+	}
+	b.renderBoard()
+	b.publish()
+	return true
+}
+
+func (b *Board) render() error {
+	b.submissionLock.Lock()
+	defer b.submissionLock.Unlock()
+	return b.renderBoard()
+}
+
+func (b *Board) summary() *pb.BoardSummary {
+	b.submissionLock.Lock()
+	defer b.submissionLock.Unlock()
+	return &pb.BoardSummary{
+		Homework: b.Homework.Name,
+		Cases:    int32(len(b.Homework.Cases)),
+		Users:    int32(len(b.submissions)),
+	}
+}
+
+// entry returns the entry of the user with its score and rank, or nil if the
+// user has none
+func (b *Board) entry(user string) *pb.Entry {
+	b.submissionLock.Lock()
+	defer b.submissionLock.Unlock()
+	for _, row := range b.Rows() {
+		if row.Submission.User != user {
+			continue
+		}
+		e := &pb.Entry{
+			Submission: proto.Clone(row.Submission).(*pb.StoredSubmission),
+			Score:      scoreProto(row.Score),
+		}
+		if row.rank > 0 {
+			e.Rank = int32(row.rank)
+		}
+		return e
+	}
+	return nil
+}
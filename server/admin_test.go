@@ -0,0 +1,196 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/NTHU-lsalab/sb/server"
+	"github.com/NTHU-lsalab/sb/server/servertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mutableConfig is a config that changes between reloads
+type mutableConfig struct {
+	homeworks []*pb.Homework
+}
+
+func (c *mutableConfig) Homeworks() ([]*pb.Homework, error) {
+	return c.homeworks, nil
+}
+
+func readAuditLog(t *testing.T, buf *bytes.Buffer) []*pb.AuditEntry {
+	var entries []*pb.AuditEntry
+	require.NoError(t, server.ReadAuditLog(bytes.NewReader(buf.Bytes()), func(e *pb.AuditEntry) error {
+		entries = append(entries, e)
+		return nil
+	}))
+	return entries
+}
+
+func TestAdminOnlyOnUnixSocket(t *testing.T) {
+	var logBuf bytes.Buffer
+	h, err := servertest.New(server.Options{
+		Config:   server.StaticConfig{{Name: "hw", Cases: []string{"01"}}},
+		AuditLog: server.NewAuditLog(&logBuf),
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	_, err = h.Admin.ListBoards(context.Background(), &pb.ListBoardsRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = h.Admin.DeleteEntry(context.Background(), &pb.EntryRequest{Homework: "hw", User: "ipc21s001"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	entries := readAuditLog(t, &logBuf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "list_boards", entries[0].Action)
+	assert.Equal(t, "refused", entries[0].Outcome)
+	assert.Equal(t, "delete", entries[1].Action)
+	assert.Equal(t, "ipc21s001", entries[1].User)
+	assert.Contains(t, entries[1].Error, "unix socket")
+}
+
+func TestAdminActions(t *testing.T) {
+	ctx := context.Background()
+	var logBuf bytes.Buffer
+	storage := servertest.NewMemoryStorage()
+	renderer := &servertest.RecordingRenderer{}
+	config := server.StaticConfig{{Name: "hw", Cases: []string{"01", "02"}}}
+	h, err := servertest.NewUnix(server.Options{
+		Config:   config,
+		Storage:  storage,
+		Renderer: renderer,
+		AuditLog: server.NewAuditLog(&logBuf),
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	submit := func(user string, times ...float64) {
+		sub := &pb.UserSubmission{User: user, Homework: "hw"}
+		for i, time := range times {
+			sub.Results = append(sub.Results, &pb.Result{Case: config[0].Cases[i], Passed: true, Time: time})
+		}
+		_, err := h.Client.Submit(ctx, sub)
+		require.NoError(t, err)
+	}
+	submit("ipc21s001", 1, 1)
+	submit("ipc21s002", 2, 2)
+
+	boards, err := h.Admin.ListBoards(ctx, &pb.ListBoardsRequest{})
+	require.NoError(t, err)
+	require.Len(t, boards.Boards, 1)
+	assert.Equal(t, &pb.BoardSummary{Homework: "hw", Cases: 2, Users: 2}, boards.Boards[0])
+
+	reply, err := h.Admin.Disqualify(ctx, &pb.DisqualifyRequest{Homework: "hw", User: "ipc21s001", Reason: "copied"})
+	require.NoError(t, err)
+	assert.Equal(t, "disqualified ipc21s001", reply.Message)
+	board, err := h.Admin.ListUsers(ctx, &pb.ListUsersRequest{Homework: "hw"})
+	require.NoError(t, err)
+	require.Len(t, board.Rows, 2)
+	assert.Equal(t, "ipc21s002", board.Rows[0].User)
+	assert.EqualValues(t, 1, board.Rows[0].Rank)
+	assert.Equal(t, "ipc21s001", board.Rows[1].User)
+	assert.True(t, board.Rows[1].Disqualified)
+	assert.Zero(t, board.Rows[1].Rank)
+
+	_, err = h.Admin.AddNote(ctx, &pb.NoteRequest{Homework: "hw", User: "ipc21s001", Text: "asked by email"})
+	require.NoError(t, err)
+	_, err = h.Admin.AddNote(ctx, &pb.NoteRequest{Homework: "hw", User: "ipc21s003", Text: "nobody"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// a better submission stays disqualified and keeps the notes
+	submit("ipc21s001", 0.5, 0.5)
+	entry, err := h.Admin.GetEntry(ctx, &pb.EntryRequest{Homework: "hw", User: "ipc21s001"})
+	require.NoError(t, err)
+	assert.True(t, entry.Submission.Disqualified)
+	assert.Zero(t, entry.Rank)
+	assert.Equal(t, 1.0, entry.Score.TotalTime)
+	require.Len(t, entry.Submission.Notes, 2)
+	assert.Equal(t, "disqualified: copied", entry.Submission.Notes[0].Text)
+	assert.Equal(t, "asked by email", entry.Submission.Notes[1].Text)
+	assert.Regexp(t, `^uid=\d+`, entry.Submission.Notes[0].Peer)
+	assert.True(t, storage.Get("hw", "ipc21s001").Disqualified)
+
+	_, err = h.Admin.ResetEntry(ctx, &pb.EntryRequest{Homework: "hw", User: "ipc21s001"})
+	require.NoError(t, err)
+	stored := storage.Get("hw", "ipc21s001")
+	assert.Empty(t, stored.Results)
+	assert.True(t, stored.Disqualified)
+	assert.Len(t, stored.Notes, 2)
+
+	_, err = h.Admin.DeleteEntry(ctx, &pb.EntryRequest{Homework: "hw", User: "ipc21s002"})
+	require.NoError(t, err)
+	assert.Nil(t, storage.Get("hw", "ipc21s002"))
+	_, err = h.Admin.GetEntry(ctx, &pb.EntryRequest{Homework: "hw", User: "ipc21s002"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	renders := renderer.Renders("hw")
+	_, err = h.Admin.Render(ctx, &pb.RenderRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, renders+1, renderer.Renders("hw"))
+
+	var actions []string
+	for _, e := range readAuditLog(t, &logBuf) {
+		actions = append(actions, e.Action+" "+e.Outcome)
+	}
+	assert.Equal(t, []string{
+		"submit created", "submit created", "list_boards ok", "disqualify ok", "list_users ok",
+		"note ok", "note refused", "submit updated", "show ok", "reset ok", "delete ok",
+		"show refused", "render ok",
+	}, actions)
+
+	// the admin actions are replayed along with the submissions
+	replayedStorage := servertest.NewMemoryStorage()
+	replayed, err := server.New(server.Options{
+		Config:  config,
+		Storage: replayedStorage,
+		Logger:  log.New(ioutil.Discard, "", 0),
+	})
+	require.NoError(t, err)
+	summary, err := replayed.Replay(bytes.NewReader(logBuf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, server.ReplaySummary{Entries: 13, Replayed: 3, Applied: 4, Refused: 2, Skipped: 4}, summary)
+	assert.Equal(t, stored.Notes[0].Text, replayedStorage.Get("hw", "ipc21s001").Notes[0].Text)
+	assert.True(t, replayedStorage.Get("hw", "ipc21s001").Disqualified)
+	assert.Nil(t, replayedStorage.Get("hw", "ipc21s002"))
+}
+
+func TestAdminReloadConfig(t *testing.T) {
+	ctx := context.Background()
+	config := &mutableConfig{homeworks: []*pb.Homework{
+		{Name: "hw1", Cases: []string{"01", "02"}, PenaltyTime: 10},
+		{Name: "hw2", Cases: []string{"01"}},
+	}}
+	h, err := servertest.NewUnix(server.Options{Config: config})
+	require.NoError(t, err)
+	defer h.Close()
+
+	_, err = h.Client.Submit(ctx, &pb.UserSubmission{User: "ipc21s001", Homework: "hw1", Results: []*pb.Result{
+		{Case: "01", Passed: true, Time: 1},
+	}})
+	require.NoError(t, err)
+
+	config.homeworks = []*pb.Homework{
+		{Name: "hw1", Cases: []string{"01", "02"}, PenaltyTime: 20},
+		{Name: "hw3", Cases: []string{"01"}},
+	}
+	reply, err := h.Admin.ReloadConfig(ctx, &pb.ReloadConfigRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "2 homeworks, added [hw3], changed [hw1], removed [hw2]", reply.Message)
+
+	entry, err := h.Admin.GetEntry(ctx, &pb.EntryRequest{Homework: "hw1", User: "ipc21s001"})
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, entry.Score.PenaltyTime)
+	hw, err := h.Client.QueryHomework(ctx, &pb.QueryHomeworkRequest{Name: "hw3"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01"}, hw.Cases)
+	_, err = h.Client.QueryHomework(ctx, &pb.QueryHomeworkRequest{Name: "hw2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Audit log outcomes besides the outcomes of SubmissionRecord
+const outcomeRefused = "refused"
+
+// AuditLog is an append-only log of the submissions and admin actions, one
+// JSON encoded AuditEntry per line
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// OpenAuditLog opens the audit log file for appending, creating it if needed
+func OpenAuditLog(filename string) (*AuditLog, error) {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{w: f}, nil
+}
+
+// NewAuditLog returns an audit log written to w
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// Record appends the entry to the log, syncing it to disk if the log is a file
+func (a *AuditLog) Record(entry *pb.AuditEntry) error {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err = a.w.Write(append(b, '\n'))
+	if err != nil {
+		return err
+	}
+	if f, ok := a.w.(*os.File); ok {
+		return f.Sync()
+	}
+	return nil
+}
+
+// Close closes the log file
+func (a *AuditLog) Close() error {
+	if c, ok := a.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// ReadAuditLog calls fn with each entry of the log in order
+func ReadAuditLog(r io.Reader, fn func(*pb.AuditEntry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		entry := &pb.AuditEntry{}
+		if err := protojson.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+		if err := fn(entry); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// audit records the entry in the audit log, if any
+func (s *Server) audit(entry *pb.AuditEntry) {
+	if s.auditLog == nil {
+		return
+	}
+	if err := s.auditLog.Record(entry); err != nil {
+		s.logger.Printf("Failed to write the audit log: %v", err)
+	}
+}
+
+// outcomeName returns the audit log outcome of the record
+func outcomeName(o pb.SubmissionRecord_Outcome) string {
+	return strings.ToLower(o.String())
+}
@@ -0,0 +1,85 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/NTHU-lsalab/sb/server"
+	"github.com/NTHU-lsalab/sb/server/servertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogReplay(t *testing.T) {
+	var logBuf bytes.Buffer
+	h, err := servertest.New(server.Options{
+		Config:   server.StaticConfig{{Name: "hw", Cases: []string{"01", "02"}}},
+		AuditLog: server.NewAuditLog(&logBuf),
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	for _, sub := range []*pb.UserSubmission{
+		{User: "ipc21s001", Homework: "hw", Results: []*pb.Result{
+			{Case: "01", Passed: true, Time: 3}, {Case: "02", Passed: true, Time: 1},
+		}},
+		{User: "ipc21s001", Homework: "hw", Results: []*pb.Result{
+			{Case: "01", Passed: true, Time: 2},
+		}},
+		{User: "ipc21s001", Homework: "hw", Results: []*pb.Result{
+			{Case: "03", Passed: true, Time: 0},
+		}},
+		{User: "ipc21s002", Homework: "nope"},
+		{User: "ipc21s003", Homework: "hw", Code: make([]byte, server.DefaultMaxSubmissionSize), Results: []*pb.Result{
+			{Case: "01", Passed: true, Time: 1},
+		}},
+	} {
+		h.Client.Submit(context.Background(), sub)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logBuf.String()), "\n")
+	require.Len(t, lines, 5)
+	var entries []*pb.AuditEntry
+	require.NoError(t, server.ReadAuditLog(strings.NewReader(logBuf.String()), func(e *pb.AuditEntry) error {
+		entries = append(entries, e)
+		return nil
+	}))
+	var outcomes []string
+	for _, e := range entries {
+		outcomes = append(outcomes, e.Outcome)
+		assert.Equal(t, "submit", e.Action)
+	}
+	assert.Equal(t, []string{"created", "not_updated", "refused", "refused", "refused"}, outcomes)
+	assert.Len(t, entries[0].Results, 2)
+	assert.NotEmpty(t, entries[0].Record.ResultsHash)
+	assert.Contains(t, entries[2].Error, `unknown case "03"`)
+	assert.Contains(t, entries[3].Error, `no such homework "nope"`)
+	assert.Contains(t, entries[4].Error, "exceeds the limit")
+	// refused submissions are logged without their results
+	for _, e := range entries[2:] {
+		assert.Empty(t, e.Results, e.User)
+	}
+	assert.Less(t, len(lines[4]), 1024)
+
+	// case 02 is dropped from the homework, which makes the second submission better
+	storage := servertest.NewMemoryStorage()
+	replayed, err := server.New(server.Options{
+		Config:  server.StaticConfig{{Name: "hw", Cases: []string{"01"}}},
+		Storage: storage,
+		Logger:  log.New(ioutil.Discard, "", 0),
+	})
+	require.NoError(t, err)
+	summary, err := replayed.Replay(strings.NewReader(logBuf.String()))
+	require.NoError(t, err)
+	assert.Equal(t, server.ReplaySummary{Entries: 5, Replayed: 2, Refused: 3}, summary)
+	stored := storage.Get("hw", "ipc21s001")
+	require.NotNil(t, stored)
+	assert.Equal(t, entries[1].Record.Id, stored.Id)
+	assert.Equal(t, entries[1].TimeUnixNano, stored.TimeUnixNano)
+	assert.Len(t, stored.Results, 1)
+}
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NTHU-lsalab/sb/pb"
+)
+
+// BoardEntry = Score + Submission
+type BoardEntry struct {
+	Score
+	Submission *pb.StoredSubmission
+}
+
+// Board contains all the information for a homework
+type Board struct {
+	Homework       *pb.Homework // replaced by ReloadConfig while holding submissionLock
+	submissions    map[string]BoardEntry
+	submissionLock sync.Mutex
+	server         *Server
+	watch          boardWatch
+}
+
+func isStudent(username string) bool {
+	return strings.HasPrefix(username, "ipc21s")
+}
+
+// ranked tells whether the entry takes part in the ranking
+func ranked(entry BoardEntry) bool {
+	return isStudent(entry.Submission.User) && !entry.Submission.Disqualified
+}
+
+// homework returns the current homework of the board
+func (b *Board) homework() *pb.Homework {
+	b.submissionLock.Lock()
+	defer b.submissionLock.Unlock()
+	return b.Homework
+}
+
+// Rows is for use in template
+func (b *Board) Rows() []TableRow {
+	rows := make([]TableRow, 0, len(b.submissions))
+	for _, boardEntry := range b.submissions {
+		rows = append(rows, TableRow{
+			BoardEntry: boardEntry,
+			Cells:      make([]TableCell, len(b.Homework.Cases)),
+		})
+	}
+	caseMap := caseMapFromHomework(b.Homework)
+	for rowi, row := range rows {
+		for _, result := range row.BoardEntry.Submission.Results {
+			if casei, ok := caseMap[result.Case]; ok {
+				rows[rowi].Cells[casei].result = result
+			}
+		}
+		rows[rowi].metrics = make([]string, len(b.Homework.MetricColumns))
+		for coli, col := range b.Homework.MetricColumns {
+			rows[rowi].metrics[coli] = aggregateMetric(col, rows[rowi].Cells)
+		}
+	}
+	sort.Slice(
+		rows,
+		func(i, j int) bool {
+			// disqualified users go to the bottom
+			if di, dj := rows[i].Submission.Disqualified, rows[j].Submission.Disqualified; di != dj {
+				return dj
+			}
+			return rows[i].Score.Better(rows[j].Score)
+		},
+	)
+	rank := 0
+	for i := range rows {
+		if ranked(rows[i].BoardEntry) {
+			rank++
+			rows[i].rank = rank
+		} else {
+			rows[i].rank = -1
+		}
+	}
+
+	for i := range b.Homework.Cases {
+		best := math.Inf(1)
+		for _, row := range rows {
+			if !ranked(row.BoardEntry) {
+				continue
+			}
+			r := row.Cells[i].result
+			if r != nil {
+				if r.Passed && r.Time < best {
+					best = r.Time
+				}
+			}
+		}
+		for _, row := range rows {
+			if !ranked(row.BoardEntry) {
+				continue
+			}
+			r := row.Cells[i].result
+			if r != nil {
+				if r.Passed && r.Time-0.1 < best {
+					row.Cells[i].best = true
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// aggregateMetric summarizes the metric of the column over the passed cells
+func aggregateMetric(col *pb.MetricColumn, cells []TableCell) string {
+	var values []float64
+	for _, cell := range cells {
+		if cell.result == nil || !cell.result.Passed {
+			continue
+		}
+		if v, ok := cell.result.Metrics[col.Metric]; ok {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return "—"
+	}
+	agg := values[0]
+	for _, v := range values[1:] {
+		switch col.Aggregate {
+		case "min":
+			agg = math.Min(agg, v)
+		case "max":
+			agg = math.Max(agg, v)
+		default:
+			agg += v
+		}
+	}
+	if col.Aggregate == "mean" {
+		agg /= float64(len(values))
+	}
+	return fmt.Sprintf(col.Format, agg)
+}
+
+// TableRow is a helper object use in html template
+type TableRow struct {
+	BoardEntry
+	rank    int
+	metrics []string
+	Cells   []TableCell
+}
+
+// Metrics returns the values of the metric columns of the row
+func (tr TableRow) Metrics() []string {
+	return tr.metrics
+}
+
+// Rank returns the rank of the row, "DQ" if disqualified, or "-" if unapplicable
+func (tr TableRow) Rank() string {
+	if tr.Submission.Disqualified {
+		return "DQ"
+	}
+	if tr.rank < 0 {
+		return "—"
+	}
+	return strconv.Itoa(tr.rank)
+}
+
+// TableCell is a helper object in a html template which corresponds to a <td>
+type TableCell struct {
+	result *pb.Result
+	best   bool
+}
+
+// Class returns the class attribute of the <td>
+func (tc TableCell) Class() string {
+	if tc.best {
+		return "best"
+	}
+	r := tc.result
+	if r == nil {
+		return "empty"
+	}
+	if !r.Passed {
+		return "failed"
+	}
+	return ""
+}
+
+// Value returns the value enclosed within the <td> tag
+func (tc TableCell) Value() string {
+	r := tc.result
+	if r == nil {
+		return "—"
+	}
+	return fmt.Sprintf("%.2f", r.Time)
+}
+
+// Title returns the title attribute of the <td> tag
+func (tc TableCell) Title() string {
+	if tc.result == nil {
+		return "not submitted"
+	}
+	return tc.result.Verdict
+}
+
+// renderBoard renders the board with submissionLock held, logging the error if
+// it fails
+func (b *Board) renderBoard() error {
+	if b.server.renderer == nil {
+		return nil
+	}
+	t0 := b.server.clock.Now()
+	err := b.server.renderer.Render(b)
+	if err != nil {
+		b.server.logger.Printf("Failed to render %s: %v", b.Homework.Name, err)
+		return err
+	}
+	t1 := b.server.clock.Now()
+	b.server.logger.Printf("Rendered %s: %d submissions in %s",
+		b.Homework.Name, len(b.submissions), t1.Sub(t0))
+	return nil
+}
+
+// updateSubmission stores the submission if it is better than the stored one,
+// and fills in the outcome and scores of the record. The submission is recorded
+// in the audit log while holding the lock, so that the order of the log is the
+// order of the updates.
+func (b *Board) updateSubmission(new *pb.UserSubmission, record *pb.SubmissionRecord, peer string) string {
+	b.submissionLock.Lock()
+	defer b.submissionLock.Unlock()
+	defer func() {
+		b.server.audit(&pb.AuditEntry{
+			TimeUnixNano: record.TimeUnixNano,
+			Action:       "submit",
+			Peer:         peer,
+			Homework:     new.Homework,
+			User:         new.User,
+			Outcome:      outcomeName(record.Outcome),
+			Record:       record,
+			Results:      new.Results,
+		})
+	}()
+	old, ok := b.submissions[new.User]
+	newScore := calcScore(b.Homework, new.Results)
+	record.NewScore = scoreProto(newScore)
+	if ok {
+		record.OldScore = scoreProto(old.Score)
+	}
+	if !ok || newScore.Better(old.Score) { // new <= old
+		stored := &pb.StoredSubmission{
+			User:         new.User,
+			Results:      new.Results,
+			Id:           record.Id,
+			TimeUnixNano: record.TimeUnixNano,
+		}
+		if ok {
+			stored.Disqualified = old.Submission.Disqualified
+			stored.Notes = old.Submission.Notes
+		}
+		b.submissions[new.User] = BoardEntry{Score: newScore, Submission: stored}
+
+		storeErr := b.server.storage.Store(b.Homework.Name, b.submissions[new.User].Submission)
+		if storeErr != nil {
+			b.server.logger.Printf("Failed to store submission %s/%s: %v", new.Homework, new.User, storeErr)
+		}
+
+		b.renderBoard()
+		b.publish()
+
+		if !ok {
+			record.Outcome = pb.SubmissionRecord_CREATED
+			return fmt.Sprintf("created %v", newScore)
+		}
+		record.Outcome = pb.SubmissionRecord_UPDATED
+		return fmt.Sprintf("updated %v --> %v", old.Score, newScore)
+	}
+	record.Outcome = pb.SubmissionRecord_NOT_UPDATED
+	return fmt.Sprintf("not updating %v -x-> %v", old.Score, newScore)
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/NTHU-lsalab/sb"
+	"github.com/NTHU-lsalab/sb/pb"
+)
+
+// ConfigSource provides the homeworks of the scoreboard
+type ConfigSource interface {
+	Homeworks() ([]*pb.Homework, error)
+}
+
+// ConfigDir loads the homeworks from the *.toml files in the directory
+type ConfigDir string
+
+// Homeworks implements ConfigSource
+func (d ConfigDir) Homeworks() (homeworks []*pb.Homework, err error) {
+	glob, err := filepath.Glob(filepath.Join(string(d), "*.toml"))
+	if err != nil {
+		panic(err) // malformed glob
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	for _, filename := range glob {
+		homeworks = append(homeworks, sb.LoadHomework(filename))
+	}
+	return homeworks, nil
+}
+
+// StaticConfig is a fixed list of homeworks
+type StaticConfig []*pb.Homework
+
+// Homeworks implements ConfigSource
+func (c StaticConfig) Homeworks() ([]*pb.Homework, error) {
+	return c, nil
+}
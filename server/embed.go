@@ -1,4 +1,4 @@
-package main
+package server
 
 const htmlTemplateString = `<!doctype html>
 <html lang="en">
@@ -76,6 +76,9 @@ const htmlTemplateString = `<!doctype html>
               <th scope="col">Passed</th>
               <th scope="col">Time</th>
               <th scope="col">Penalty</th>
+              {{range $col := .Homework.MetricColumns}}
+              <th scope="col">{{$col.Title}}</th>
+              {{end}}
               {{range $name := .Homework.Cases}}
               <th>{{$name}}</th>
               {{end}}
@@ -91,6 +94,9 @@ const htmlTemplateString = `<!doctype html>
                 {{$row.TotalTime | printf "%.2f"}}
               </td>
               {{if gt $row.PenaltyTime 0.0}}<td class="penalty">{{$row.PenaltyTime | printf "%.0f"}}</td>{{else}}<td></td>{{end}}
+              {{range $value := $row.Metrics}}
+              <td>{{$value}}</td>
+              {{end}}
               {{range $cell := $row.Cells}}
               <td class="{{$cell.Class}}" title="{{$cell.Title}}">{{$cell.Value}}</td>
               {{end}}
@@ -112,7 +118,7 @@ const htmlTemplateString = `<!doctype html>
     <script>
       var table = document.getElementById("thetable");
       
-      for (var i = 5; i < table.rows[0].cells.length; i++) {
+      for (var i = 5 + {{len .Homework.MetricColumns}}; i < table.rows[0].cells.length; i++) {
         var minimum = 65536;
         for (var j = 1; j < table.rows.length; j++) {
           if (!table.rows[j].cells[i].classList.contains("failed") && !table.rows[j].cells[i].classList.contains("empty")) {
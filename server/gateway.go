@@ -0,0 +1,165 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// publicResult returns the part of the result shown on the scoreboard
+func publicResult(r *pb.Result) *pb.Result {
+	return &pb.Result{
+		Case:    r.Case,
+		Passed:  r.Passed,
+		Time:    r.Time,
+		Verdict: r.Verdict,
+		Memory:  r.Memory,
+		CpuTime: r.CpuTime,
+		Metrics: r.Metrics,
+		Timing:  r.Timing,
+	}
+}
+
+// publicHomework returns the homework without the paths of the runner and
+// fallback files, which are private to the judging machines
+func publicHomework(hw *pb.Homework) *pb.Homework {
+	hw = proto.Clone(hw).(*pb.Homework)
+	hw.Runner = ""
+	for _, f := range hw.Files {
+		f.Fallback = ""
+	}
+	return hw
+}
+
+// boardRow converts a row of the board
+func boardRow(row TableRow) *pb.BoardRow {
+	r := &pb.BoardRow{
+		User:        row.Submission.User,
+		NumPassed:   int32(row.NumPassed),
+		TotalTime:   row.TotalTime,
+		PenaltyTime: row.PenaltyTime,
+
+		Disqualified: row.Submission.Disqualified,
+	}
+	if row.rank > 0 {
+		r.Rank = int32(row.rank)
+	}
+	for _, result := range row.Submission.Results {
+		r.Results = append(r.Results, publicResult(result))
+	}
+	return r
+}
+
+// Snapshot returns the ranking of the board
+func (b *Board) Snapshot() *pb.Board {
+	b.submissionLock.Lock()
+	defer b.submissionLock.Unlock()
+	return b.snapshot()
+}
+
+// snapshot is Snapshot with submissionLock held
+func (b *Board) snapshot() *pb.Board {
+	board := &pb.Board{Homework: b.Homework.Name}
+	for _, row := range b.Rows() {
+		board.Rows = append(board.Rows, boardRow(row))
+	}
+	return board
+}
+
+// userResults returns the public results of the user, or nil if the user has
+// not submitted
+func (b *Board) userResults(user string) *pb.StoredSubmission {
+	b.submissionLock.Lock()
+	defer b.submissionLock.Unlock()
+	entry, ok := b.submissions[user]
+	if !ok {
+		return nil
+	}
+	sub := &pb.StoredSubmission{User: user}
+	for _, result := range entry.Submission.Results {
+		sub.Results = append(sub.Results, publicResult(result))
+	}
+	return sub
+}
+
+var jsonMarshaler = jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+
+// writeJSON writes the message with an ETag of its content, or 304 Not
+// Modified if the client already has it
+func writeJSON(w http.ResponseWriter, r *http.Request, msg proto.Message) {
+	var buf bytes.Buffer
+	if err := jsonMarshaler.Marshal(&buf, msg); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	for _, tag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if strings.TrimSpace(tag) == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf.Bytes())
+}
+
+// HTTPHandler returns the read-only JSON gateway of the scoreboard:
+//
+//	GET /api/homeworks                          the names of the homeworks
+//	GET /api/homeworks/{homework}               the homework, without runner and fallback paths
+//	GET /api/homeworks/{homework}/board         the rows of the board
+//	GET /api/homeworks/{homework}/users/{user}  the results of the user
+//
+// It serves what the HTML board shows, so the results have no details.
+// Submissions are only accepted over gRPC.
+func (s *Server) HTTPHandler() http.Handler {
+	return http.HandlerFunc(s.serveHTTP)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	path := strings.Trim(r.URL.Path, "/")
+	if path != "api/homeworks" && !strings.HasPrefix(path, "api/homeworks/") {
+		http.NotFound(w, r)
+		return
+	}
+	parts := strings.Split(path, "/")[2:]
+	if len(parts) == 0 {
+		writeJSON(w, r, &pb.HomeworkList{Homeworks: s.boardNames()})
+		return
+	}
+	b, ok := s.Board(parts[0])
+	if !ok {
+		http.Error(w, "no such homework", http.StatusNotFound)
+		return
+	}
+	switch {
+	case len(parts) == 1:
+		writeJSON(w, r, publicHomework(b.homework()))
+	case len(parts) == 2 && parts[1] == "board":
+		writeJSON(w, r, b.Snapshot())
+	case len(parts) == 3 && parts[1] == "users":
+		sub := b.userResults(parts[2])
+		if sub == nil {
+			http.Error(w, "no submission of the user", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, r, sub)
+	default:
+		http.NotFound(w, r)
+	}
+}
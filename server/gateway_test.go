@@ -0,0 +1,105 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/NTHU-lsalab/sb/server"
+	"github.com/NTHU-lsalab/sb/server/servertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, h http.Handler, path string, header http.Header) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var body map[string]interface{}
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	}
+	return w, body
+}
+
+func TestGateway(t *testing.T) {
+	h, err := servertest.New(server.Options{
+		Config: server.StaticConfig{
+			{
+				Name:   "hw1",
+				Runner: "/secret/runner",
+				Files:  []*pb.SourceFile{{Name: "hw1.cc", Fallback: "/secret/hw1.cc"}},
+				Cases:  []string{"01", "02"},
+			},
+			{Name: "hw0", Cases: []string{"01"}},
+		},
+	})
+	require.NoError(t, err)
+	defer h.Close()
+	for _, sub := range []*pb.UserSubmission{
+		{User: "ipc21s001", Homework: "hw1", Results: []*pb.Result{
+			{Case: "01", Passed: true, Time: 1, Verdict: "accepted", Details: "secret output"},
+		}},
+		{User: "ta", Homework: "hw1", Results: []*pb.Result{
+			{Case: "01", Passed: true, Time: 1}, {Case: "02", Passed: true, Time: 1},
+		}},
+	} {
+		_, err := h.Client.Submit(context.Background(), sub)
+		require.NoError(t, err)
+	}
+	handler := h.Server.HTTPHandler()
+
+	_, body := get(t, handler, "/api/homeworks", nil)
+	assert.Equal(t, []interface{}{"hw0", "hw1"}, body["homeworks"])
+
+	_, body = get(t, handler, "/api/homeworks/hw1", nil)
+	assert.Equal(t, "hw1", body["name"])
+	assert.Equal(t, "", body["runner"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "hw1.cc", "fallback": ""}}, body["files"])
+	assert.Contains(t, body, "penalty_time")
+
+	w, body := get(t, handler, "/api/homeworks/hw1/board", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	rows := body["rows"].([]interface{})
+	require.Len(t, rows, 2)
+	assert.Equal(t, "ta", rows[0].(map[string]interface{})["user"])
+	assert.Equal(t, 0.0, rows[0].(map[string]interface{})["rank"])
+	assert.Equal(t, "ipc21s001", rows[1].(map[string]interface{})["user"])
+	assert.Equal(t, 1.0, rows[1].(map[string]interface{})["rank"])
+	assert.Equal(t, 1.0, rows[1].(map[string]interface{})["num_passed"])
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	w, _ = get(t, handler, "/api/homeworks/hw1/board", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	_, err = h.Client.Submit(context.Background(), &pb.UserSubmission{User: "ipc21s002", Homework: "hw1"})
+	require.NoError(t, err)
+	w, _ = get(t, handler, "/api/homeworks/hw1/board", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	w, body = get(t, handler, "/api/homeworks/hw1/users/ipc21s001", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	results := body["results"].([]interface{})
+	require.Len(t, results, 1)
+	assert.Equal(t, "accepted", results[0].(map[string]interface{})["verdict"])
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	for _, path := range []string{"/api/homeworks/hw2", "/api/homeworks/hw1/users/nobody", "/api/homeworks/hw1/x", "/"} {
+		w, _ = get(t, handler, path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/homeworks", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,55 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/NTHU-lsalab/sb/server"
+	"github.com/NTHU-lsalab/sb/server/servertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestPeerCredentials(t *testing.T) {
+	var auditLog bytes.Buffer
+	s, err := server.New(server.Options{
+		Config:   server.StaticConfig{{Name: "hw", Cases: []string{"01"}}},
+		Storage:  servertest.NewMemoryStorage(),
+		AuditLog: server.NewAuditLog(&auditLog),
+		Logger:   log.New(ioutil.Discard, "", 0),
+	})
+	require.NoError(t, err)
+	dir, err := ioutil.TempDir("", "peer-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	addr := filepath.Join(dir, "sb.sock")
+	lis, err := net.Listen("unix", addr)
+	require.NoError(t, err)
+	gs := grpc.NewServer(grpc.Creds(server.PeerCredentials()))
+	pb.RegisterScoreboardServer(gs, s)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	conn, err := grpc.Dial("unix://"+addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = pb.NewScoreboardClient(conn).Submit(context.Background(), &pb.UserSubmission{User: "ipc21s001", Homework: "hw"})
+	require.NoError(t, err)
+
+	var entries []*pb.AuditEntry
+	require.NoError(t, server.ReadAuditLog(&auditLog, func(e *pb.AuditEntry) error {
+		entries = append(entries, e)
+		return nil
+	}))
+	require.Len(t, entries, 1)
+	assert.Regexp(t, fmt.Sprintf(`^uid=%d\(.*\) pid=%d$`, os.Getuid(), os.Getpid()), entries[0].Peer)
+}
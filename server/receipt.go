@@ -0,0 +1,121 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// LoadKey loads the receipt signing key from the file
+func LoadKey(filename string) (ed25519.PrivateKey, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := hex.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("%s is not a receipt key", filename)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// LoadOrCreateKey loads the receipt signing key from the file, creating a new
+// key if the file does not exist
+func LoadOrCreateKey(filename string) (ed25519.PrivateKey, error) {
+	key, err := LoadKey(filename)
+	if !os.IsNotExist(err) {
+		return key, err
+	}
+	_, key, err = ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(filename, []byte(hex.EncodeToString(key.Seed())+"\n"), 0600)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// KeyID returns the short identifier of the public key stated in receipts
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// resultsHash returns the hash of the results
+func resultsHash(results []*pb.Result) string {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(&pb.StoredSubmission{Results: results})
+	if err != nil {
+		panic(err) // results received over grpc can always be marshaled
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// newRecord describes the submission received at the time, before it is scored
+func newRecord(sub *pb.UserSubmission, now time.Time) *pb.SubmissionRecord {
+	record := &pb.SubmissionRecord{
+		Homework:     sub.Homework,
+		User:         sub.User,
+		TimeUnixNano: now.UnixNano(),
+		ResultsHash:  resultsHash(sub.Results),
+		CodeHash:     sub.CodeHash,
+	}
+	if record.CodeHash == "" && len(sub.Code) > 0 {
+		sum := sha256.Sum256(sub.Code)
+		record.CodeHash = hex.EncodeToString(sum[:])
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%s\x00%s",
+		record.Homework, record.User, record.TimeUnixNano, record.ResultsHash, record.CodeHash)))
+	record.Id = hex.EncodeToString(sum[:10])
+	return record
+}
+
+func scoreProto(s Score) *pb.Score {
+	return &pb.Score{
+		NumPassed:   int32(s.NumPassed),
+		TotalTime:   s.TotalTime,
+		PenaltyTime: s.PenaltyTime,
+	}
+}
+
+// signReceipt signs the record with the key
+func signReceipt(key ed25519.PrivateKey, record *pb.SubmissionRecord) (*pb.Receipt, error) {
+	b, err := proto.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Receipt{
+		Record:    b,
+		Signature: ed25519.Sign(key, b),
+		KeyId:     KeyID(key.Public().(ed25519.PublicKey)),
+	}, nil
+}
+
+// VerifyReceipt checks the signature of the receipt with the public key, and
+// returns the signed record
+func VerifyReceipt(pub ed25519.PublicKey, receipt *pb.Receipt) (*pb.SubmissionRecord, error) {
+	if receipt.KeyId != KeyID(pub) {
+		return nil, fmt.Errorf("receipt signed by key %s, not %s", receipt.KeyId, KeyID(pub))
+	}
+	if !ed25519.Verify(pub, receipt.Record, receipt.Signature) {
+		return nil, errors.New("bad signature")
+	}
+	record := &pb.SubmissionRecord{}
+	if err := proto.Unmarshal(receipt.Record, record); err != nil {
+		return nil, fmt.Errorf("bad record: %v", err)
+	}
+	return record, nil
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOrCreateKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "receipt-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "receipt.key")
+
+	_, err = LoadKey(filename)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filename)
+	assert.True(t, os.IsNotExist(err))
+
+	key, err := LoadOrCreateKey(filename)
+	require.NoError(t, err)
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := LoadOrCreateKey(filename)
+	require.NoError(t, err)
+	assert.Equal(t, key, loaded)
+	loaded, err = LoadKey(filename)
+	require.NoError(t, err)
+	assert.Equal(t, key, loaded)
+
+	require.NoError(t, ioutil.WriteFile(filename, []byte("garbage\n"), 0600))
+	_, err = LoadOrCreateKey(filename)
+	assert.EqualError(t, err, filename+" is not a receipt key")
+}
+
+func TestReceipt(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pub := key.Public().(ed25519.PublicKey)
+	now := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	sub := &pb.UserSubmission{
+		User:     "ipc21s001",
+		Homework: "hw",
+		Results:  []*pb.Result{{Case: "01", Passed: true, Time: 1, Metrics: map[string]float64{"a": 1, "b": 2}}},
+		CodeHash: "abc",
+	}
+	record := newRecord(sub, now)
+	assert.Equal(t, record, newRecord(sub, now), "the id and hashes are stable")
+	assert.Len(t, record.Id, 20)
+	assert.Equal(t, "abc", record.CodeHash)
+	assert.NotEqual(t, record.Id, newRecord(sub, now.Add(time.Nanosecond)).Id)
+
+	receipt, err := signReceipt(key, record)
+	require.NoError(t, err)
+	verified, err := VerifyReceipt(pub, receipt)
+	require.NoError(t, err)
+	assert.Equal(t, record.Id, verified.Id)
+	assert.Equal(t, record.ResultsHash, verified.ResultsHash)
+
+	receipt.Record[len(receipt.Record)-1] ^= 1
+	_, err = VerifyReceipt(pub, receipt)
+	assert.EqualError(t, err, "bad signature")
+
+	seed := make([]byte, ed25519.SeedSize)
+	seed[0] = 1
+	other := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	_, err = VerifyReceipt(other, receipt)
+	assert.Contains(t, err.Error(), "receipt signed by key "+KeyID(pub))
+}
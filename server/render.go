@@ -0,0 +1,39 @@
+package server
+
+import (
+	"html/template"
+	"os"
+	"path/filepath"
+)
+
+var htmlTemplate = template.Must(template.New("template.html").Parse(htmlTemplateString))
+
+// Renderer publishes a board whenever it changes
+type Renderer interface {
+	Render(b *Board) error
+}
+
+// HTMLRenderer renders each board to Dir/<homework>/index.html
+type HTMLRenderer struct {
+	Dir string
+}
+
+// Render implements Renderer
+func (r *HTMLRenderer) Render(b *Board) error {
+	dirname := filepath.Join(r.Dir, b.Homework.Name)
+	err := os.MkdirAll(dirname, 0755)
+	if err != nil {
+		return err
+	}
+	filename := filepath.Join(dirname, "index.html")
+	w, err := os.Create(filename + "-")
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	err = htmlTemplate.Execute(w, b)
+	if err != nil {
+		return err
+	}
+	return os.Rename(filename+"-", filename)
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"io"
+	"time"
+
+	"github.com/NTHU-lsalab/sb/pb"
+)
+
+// ReplaySummary counts the entries of a replayed audit log
+type ReplaySummary struct {
+	Entries  int // entries read
+	Replayed int // submissions scored again
+	Applied  int // admin actions that changed a board, applied again
+	Refused  int // submissions and admin actions that were refused at the time
+	Skipped  int // entries that no longer apply or do not change the boards, such as submissions to removed homeworks
+}
+
+// Replay scores the submissions of the audit log again in order, with the
+// current homework configs, and applies the admin actions that changed the
+// boards. Replaying the log of a server into an empty storage rebuilds the
+// storage of the server, after applying changes of the scoring policy.
+func (s *Server) Replay(r io.Reader) (ReplaySummary, error) {
+	var summary ReplaySummary
+	err := ReadAuditLog(r, func(entry *pb.AuditEntry) error {
+		summary.Entries++
+		switch {
+		case entry.Outcome == outcomeRefused:
+			summary.Refused++
+		case entry.Action == "submit":
+			if s.replaySubmit(entry) {
+				summary.Replayed++
+			} else {
+				summary.Skipped++
+			}
+		case adminChange(entry) != nil:
+			if s.replayAdmin(entry) {
+				summary.Applied++
+			} else {
+				summary.Skipped++
+			}
+		default:
+			summary.Skipped++
+		}
+		return nil
+	})
+	return summary, err
+}
+
+// replayAdmin applies the admin action of the entry again
+func (s *Server) replayAdmin(entry *pb.AuditEntry) bool {
+	board, ok := s.Board(entry.Homework)
+	if !ok {
+		s.logger.Printf("Skipping %s of %s: no such homework %q", entry.Action, entry.User, entry.Homework)
+		return false
+	}
+	if _, err := board.adminUpdate(entry, adminChange(entry)); err != nil {
+		s.logger.Printf("Skipping %s of %s/%s: %v", entry.Action, entry.Homework, entry.User, err)
+		return false
+	}
+	return true
+}
+
+// replaySubmit scores the submission of the entry, keeping its id and time
+func (s *Server) replaySubmit(entry *pb.AuditEntry) bool {
+	sub := &pb.UserSubmission{
+		User:     entry.User,
+		Homework: entry.Homework,
+		Results:  entry.Results,
+		CodeHash: entry.Record.GetCodeHash(),
+	}
+	board, ok := s.Board(sub.Homework)
+	if !ok {
+		s.logger.Printf("Skipping submission %s: no such homework %q", entry.Record.GetId(), sub.Homework)
+		return false
+	}
+	// results of cases removed from the homework since are dropped
+	caseMap := caseMapFromHomework(board.homework())
+	var results []*pb.Result
+	for _, result := range sub.Results {
+		if _, ok := caseMap[result.Case]; ok {
+			results = append(results, result)
+		}
+	}
+	sub.Results = results
+	if err := validateSubmission(board.homework(), sub, s.maxSubmissionSize); err != nil {
+		s.logger.Printf("Skipping submission %s of %s/%s: %v", entry.Record.GetId(), sub.Homework, sub.User, err)
+		return false
+	}
+	record := newRecord(sub, time.Unix(0, entry.TimeUnixNano))
+	if entry.Record != nil {
+		record.Id = entry.Record.Id
+	}
+	board.updateSubmission(sub, record, entry.Peer)
+	return true
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/NTHU-lsalab/sb/pb"
+)
+
+// Score is used to rank BoardEntries
+type Score struct {
+	NumPassed   int
+	TotalTime   float64
+	PenaltyTime float64
+}
+
+func (s Score) String() string {
+	return fmt.Sprintf("{%d %.2f}", s.NumPassed, s.TotalTime)
+}
+
+// Better returns whether the score s is better than o
+func (s Score) Better(o Score) bool {
+	if s.NumPassed == o.NumPassed {
+		return s.TotalTime < o.TotalTime
+	}
+	return s.NumPassed > o.NumPassed
+}
+
+func caseMapFromHomework(hw *pb.Homework) map[string]int {
+	caseMap := make(map[string]int)
+	for i, casename := range hw.Cases {
+		caseMap[casename] = i
+	}
+	return caseMap
+}
+
+func calcScore(hw *pb.Homework, results []*pb.Result) (s Score) {
+	caseMap := caseMapFromHomework(hw)
+	stats := make([]struct {
+		Passed bool
+		Time   float64
+	}, len(hw.Cases))
+	for _, result := range results {
+		i, ok := caseMap[result.Case]
+		if !ok {
+			continue
+		}
+		if result.Passed {
+			stats[i].Time = result.Time
+			stats[i].Passed = true
+		}
+	}
+	for _, stat := range stats {
+		if stat.Passed {
+			s.NumPassed++
+			s.TotalTime += stat.Time
+		} else {
+			s.PenaltyTime += hw.PenaltyTime
+		}
+	}
+	return
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcScore(t *testing.T) {
+	hw := &pb.Homework{Cases: []string{"01", "02", "03"}, PenaltyTime: 10}
+	s := calcScore(hw, []*pb.Result{
+		{Case: "01", Passed: true, Time: 1.5},
+		{Case: "02", Passed: false, Time: 2},
+		{Case: "03", Passed: true, Time: 0.5},
+		{Case: "04", Passed: true, Time: 100},
+	})
+	assert.Equal(t, Score{NumPassed: 2, TotalTime: 2, PenaltyTime: 10}, s)
+}
+
+func TestScoreBetter(t *testing.T) {
+	assert.True(t, Score{NumPassed: 2, TotalTime: 9}.Better(Score{NumPassed: 1, TotalTime: 1}))
+	assert.True(t, Score{NumPassed: 2, TotalTime: 1}.Better(Score{NumPassed: 2, TotalTime: 9}))
+	assert.False(t, Score{NumPassed: 2, TotalTime: 1}.Better(Score{NumPassed: 2, TotalTime: 1}))
+}
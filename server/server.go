@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NTHU-lsalab/sb"
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Clock tells the time
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Options are the dependencies of a Server
+type Options struct {
+	Config   ConfigSource // the homeworks
+	Storage  Storage      // the best submission of each user
+	Renderer Renderer     // publishes the boards, if not nil
+	Clock    Clock        // defaults to the system clock
+	Logger   *log.Logger  // defaults to the standard logger's output
+
+	// ReceiptKey signs the receipts of submissions, none are issued if nil
+	ReceiptKey ed25519.PrivateKey
+
+	// AuditLog records the submissions, none if nil
+	AuditLog *AuditLog
+
+	// MaxSubmissionSize limits the encoded size of submissions, DefaultMaxSubmissionSize if zero
+	MaxSubmissionSize int
+
+	// MinProtocolVersion is the oldest protocol version of clients accepted
+	// by UnaryInterceptor
+	MinProtocolVersion int
+
+	// AdminUIDs may call the Admin service, besides root and the user running
+	// the server
+	AdminUIDs []uint32
+}
+
+// Server implements the Scoreboard service
+type Server struct {
+	boardsLock sync.RWMutex // guards boards, which ReloadConfig replaces
+	boards     map[string]*Board
+	config     ConfigSource
+	storage    Storage
+	renderer   Renderer
+	clock      Clock
+	logger     *log.Logger
+
+	auditLog          *AuditLog
+	receiptKey        ed25519.PrivateKey
+	maxSubmissionSize int
+	minProtocol       int
+	epoch             int64 // distinguishes the resume tokens of each run of the server
+	adminUIDs         []uint32
+}
+
+var _ pb.ScoreboardServer = &Server{}
+
+// New loads the homeworks and their stored submissions, and renders the boards
+func New(opts Options) (*Server, error) {
+	s := &Server{
+		boards:   make(map[string]*Board),
+		config:   opts.Config,
+		storage:  opts.Storage,
+		renderer: opts.Renderer,
+		clock:    opts.Clock,
+		logger:   opts.Logger,
+
+		auditLog:          opts.AuditLog,
+		receiptKey:        opts.ReceiptKey,
+		maxSubmissionSize: opts.MaxSubmissionSize,
+		minProtocol:       opts.MinProtocolVersion,
+		adminUIDs:         opts.AdminUIDs,
+	}
+	if s.clock == nil {
+		s.clock = systemClock{}
+	}
+	if s.logger == nil {
+		s.logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+	if s.maxSubmissionSize == 0 {
+		s.maxSubmissionSize = DefaultMaxSubmissionSize
+	}
+	s.epoch = s.clock.Now().UnixNano()
+	homeworks, err := opts.Config.Homeworks()
+	if err != nil {
+		return nil, err
+	}
+	for _, hw := range homeworks {
+		b, err := s.loadBoard(hw)
+		if err != nil {
+			return nil, fmt.Errorf("could not load homework %s: %v", hw.Name, err)
+		}
+		s.boards[hw.Name] = b
+	}
+	return s, nil
+}
+
+func (s *Server) loadBoard(hw *pb.Homework) (*Board, error) {
+	b := &Board{
+		Homework:    hw,
+		submissions: make(map[string]BoardEntry),
+		server:      s,
+	}
+	submissions, err := s.storage.Load(hw.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, submission := range submissions {
+		if submission.User == "" {
+			s.logger.Printf("Skipping a stored submission of %s without user", hw.Name)
+			continue
+		}
+		b.submissions[submission.User] = BoardEntry{
+			Score:      calcScore(hw, submission.Results),
+			Submission: submission,
+		}
+	}
+	b.renderBoard()
+	b.watch.last = b.snapshot()
+	return b, nil
+}
+
+// Board returns the board of the homework
+func (s *Server) Board(homework string) (*Board, bool) {
+	s.boardsLock.RLock()
+	defer s.boardsLock.RUnlock()
+	b, ok := s.boards[homework]
+	return b, ok
+}
+
+// boardNames returns the sorted names of the homeworks
+func (s *Server) boardNames() []string {
+	s.boardsLock.RLock()
+	defer s.boardsLock.RUnlock()
+	names := make([]string, 0, len(s.boards))
+	for name := range s.boards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// updateSubmission validates and scores the submission received from the peer
+// at the time, and records it in the audit log
+func (s *Server) updateSubmission(new *pb.UserSubmission, now time.Time, peer string) (*pb.SubmissionRecord, string, error) {
+	board, ok := s.Board(new.Homework)
+	err := error(nil)
+	if !ok {
+		err = status.Errorf(codes.NotFound, "no such homework %q", new.Homework)
+	} else {
+		err = validateSubmission(board.homework(), new, s.maxSubmissionSize)
+	}
+	if err != nil {
+		s.audit(&pb.AuditEntry{
+			TimeUnixNano: now.UnixNano(),
+			Action:       "submit",
+			Peer:         peer,
+			Homework:     new.Homework,
+			User:         new.User,
+			Outcome:      outcomeRefused,
+			Error:        err.Error(),
+		})
+		return nil, "", err
+	}
+	record := newRecord(new, now)
+	msg := board.updateSubmission(new, record, peer)
+	return record, msg, nil
+}
+
+func (s *Server) handleSubmit(ctx context.Context, sub *pb.UserSubmission) (rep *pb.SubmissionReply, err error) {
+	record, msg, err := s.updateSubmission(sub, s.clock.Now(), peerIdentity(ctx))
+	if err != nil {
+		return
+	}
+	rep = &pb.SubmissionReply{Message: msg, Record: record}
+	if s.receiptKey != nil {
+		rep.Receipt, err = signReceipt(s.receiptKey, record)
+		if err != nil {
+			s.logger.Printf("Failed to sign receipt of %s/%s: %v", sub.Homework, sub.User, err)
+			err = nil
+		}
+	}
+	return
+}
+
+func (s *Server) Submit(ctx context.Context, sub *pb.UserSubmission) (*pb.SubmissionReply, error) {
+	rep, err := s.handleSubmit(ctx, sub)
+	if err == nil {
+		s.logger.Printf("Accepted %s/%s %s: %s", sub.Homework, sub.User, rep.Record.Id, rep.Message)
+	} else {
+		s.logger.Printf("Refused %s/%s: %v", sub.Homework, sub.User, err)
+	}
+	return rep, err
+}
+
+func (s *Server) QueryHomework(ctx context.Context, req *pb.QueryHomeworkRequest) (*pb.Homework, error) {
+	b, ok := s.Board(req.Name)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no such homework %q", req.Name)
+	}
+	hw := proto.Clone(b.homework()).(*pb.Homework)
+	hw.ServerVersion = sb.Version
+	return hw, nil
+}
+
+func (s *Server) QueryCaseTimes(ctx context.Context, req *pb.QueryCaseTimesRequest) (*pb.CaseTimes, error) {
+	b, ok := s.Board(req.Homework)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no such homework %q", req.Homework)
+	}
+	b.submissionLock.Lock()
+	defer b.submissionLock.Unlock()
+	times := &pb.CaseTimes{Times: make(map[string]float64)}
+	if entry, ok := b.submissions[req.User]; ok {
+		for _, result := range entry.Submission.Results {
+			times.Times[result.Case] = result.Time
+		}
+	}
+	return times, nil
+}
@@ -0,0 +1,235 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NTHU-lsalab/sb/judge"
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/NTHU-lsalab/sb/server"
+	"github.com/NTHU-lsalab/sb/server/servertest"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testEnv is a temporary directory with a homework whose runner reads the
+// verdicts of the cases from the environment
+type testEnv struct {
+	dir      string
+	homework *pb.Homework
+}
+
+func newTestEnv(t *testing.T) (*testEnv, func()) {
+	dir, err := ioutil.TempDir("", "server-test")
+	require.NoError(t, err)
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	os.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	cleanup := func() {
+		os.Setenv("XDG_CACHE_HOME", cacheHome)
+		os.RemoveAll(dir)
+	}
+
+	work := filepath.Join(dir, "work")
+	require.NoError(t, os.Mkdir(work, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(work, "Makefile"),
+		[]byte("hw:\n\tprintf '#!/bin/sh\\n' > hw && chmod +x hw\n"), 0644))
+	runner := filepath.Join(dir, "runner")
+	require.NoError(t, ioutil.WriteFile(runner, []byte(`#!/bin/sh
+case " $FAIL_CASES " in
+*" $1 "*) echo '{"passed":false,"time":9,"verdict":"wrong answer"}' ;;
+*) echo "{\"passed\":true,\"time\":$CASE_TIME,\"verdict\":\"accepted\"}" ;;
+esac
+`), 0755))
+
+	return &testEnv{
+		dir: dir,
+		homework: &pb.Homework{
+			Name:   "hw",
+			Target: "hw",
+			Runner: runner,
+			Files:  []*pb.SourceFile{{Name: "Makefile"}},
+			Cases:  []string{"01", "02", "03"},
+		},
+	}, cleanup
+}
+
+// judge judges the homework as the user and submits the results to the harness
+func (e *testEnv) judge(t *testing.T, h *servertest.Harness, user, caseTime, failCases string) *judge.Report {
+	os.Setenv("CASE_TIME", caseTime)
+	os.Setenv("FAIL_CASES", failCases)
+	defer os.Unsetenv("CASE_TIME")
+	defer os.Unsetenv("FAIL_CASES")
+	report, err := judge.Run(context.Background(), judge.Config{
+		Options: judge.Options{
+			Chdir:    filepath.Join(e.dir, "work"),
+			Homework: e.homework.Name,
+			Format:   judge.FormatText,
+		},
+		User:    user,
+		Logger:  log.New(ioutil.Discard, "", 0),
+		Stdout:  ioutil.Discard,
+		Stderr:  ioutil.Discard,
+		Client:  h.Client,
+		TempDir: e.dir,
+	})
+	require.NoError(t, err)
+	require.True(t, report.Submitted)
+	return report
+}
+
+func TestSubmitRankRender(t *testing.T) {
+	env, cleanup := newTestEnv(t)
+	defer cleanup()
+
+	storage := servertest.NewMemoryStorage()
+	outputDir := filepath.Join(env.dir, "html")
+	h, err := servertest.New(server.Options{
+		Config:     server.StaticConfig{env.homework},
+		Storage:    storage,
+		Renderer:   &server.HTMLRenderer{Dir: outputDir},
+		ReceiptKey: ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)),
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	report := env.judge(t, h, "ipc21s001", "2", "02")
+	assert.Equal(t, "created {2 4.00}", report.Scoreboard)
+	receipt := filepath.Join(env.dir, "cache", "xjudge", "receipts", "hw", report.SubmissionID+".json")
+	assert.FileExists(t, receipt)
+	report = env.judge(t, h, "ipc21s002", "1", "")
+	assert.Equal(t, "created {3 3.00}", report.Scoreboard)
+	report = env.judge(t, h, "ta", "0.5", "")
+	assert.Equal(t, "created {3 1.50}", report.Scoreboard)
+
+	report = env.judge(t, h, "ipc21s001", "3", "02")
+	assert.Equal(t, "not updating {2 4.00} -x-> {2 6.00}", report.Scoreboard)
+	report = env.judge(t, h, "ipc21s001", "0.1", "")
+	assert.Equal(t, "updated {2 4.00} --> {3 0.30}", report.Scoreboard)
+
+	stored := storage.Get("hw", "ipc21s001")
+	require.NotNil(t, stored)
+	assert.Len(t, stored.Results, 3)
+	assert.Equal(t, report.SubmissionID, stored.Id)
+
+	board, ok := h.Server.Board("hw")
+	require.True(t, ok)
+	rows := board.Rows()
+	require.Len(t, rows, 3)
+	assert.Equal(t, "ipc21s001", rows[0].Submission.User)
+	assert.Equal(t, "1", rows[0].Rank())
+	assert.Equal(t, "ta", rows[1].Submission.User)
+	assert.Equal(t, "—", rows[1].Rank())
+	assert.Equal(t, "ipc21s002", rows[2].Submission.User)
+	assert.Equal(t, "2", rows[2].Rank())
+
+	html, err := ioutil.ReadFile(filepath.Join(outputDir, "hw", "index.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "ipc21s001")
+	assert.Contains(t, string(html), "ipc21s002")
+
+	times, err := h.Client.QueryCaseTimes(context.Background(), &pb.QueryCaseTimesRequest{Homework: "hw", User: "ipc21s002"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"01": 1, "02": 1, "03": 1}, times.Times)
+}
+
+func TestLoadStoredSubmissions(t *testing.T) {
+	hw := &pb.Homework{Name: "hw", Cases: []string{"01"}}
+	storage := servertest.NewMemoryStorage()
+	require.NoError(t, storage.Store("hw", &pb.StoredSubmission{
+		User:    "ipc21s001",
+		Results: []*pb.Result{{Case: "01", Passed: true, Time: 1}},
+	}))
+	renderer := &servertest.RecordingRenderer{}
+	var logs bytes.Buffer
+	h, err := servertest.New(server.Options{
+		Config:   server.StaticConfig{hw},
+		Storage:  storage,
+		Renderer: renderer,
+		Logger:   log.New(&logs, "", 0),
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	assert.Equal(t, 1, renderer.Renders("hw"))
+	assert.Contains(t, logs.String(), "Rendered hw: 1 submissions")
+
+	rep, err := h.Client.Submit(context.Background(), &pb.UserSubmission{
+		Homework: "hw",
+		User:     "ipc21s001",
+		Results:  []*pb.Result{{Case: "01", Passed: true, Time: 2}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "not updating {1 1.00} -x-> {1 2.00}", rep.Message)
+	assert.Equal(t, 1, renderer.Renders("hw"))
+
+	_, err = h.Client.QueryHomework(context.Background(), &pb.QueryHomeworkRequest{Name: "nope"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = h.Client.Submit(context.Background(), &pb.UserSubmission{Homework: "nope", User: "ipc21s001"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// an invalid submission does not replace the stored one
+	_, err = h.Client.Submit(context.Background(), &pb.UserSubmission{
+		Homework: "hw",
+		User:     "ipc21s001",
+		Results:  []*pb.Result{{Case: "01", Passed: true, Time: -1}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 1.0, storage.Get("hw", "ipc21s001").Results[0].Time)
+	assert.Contains(t, logs.String(), "Refused hw/ipc21s001: rpc error: code = InvalidArgument")
+}
+
+func TestSubmitReceipt(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	clock := servertest.NewFakeClock(time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC))
+	h, err := servertest.New(server.Options{
+		Config:     server.StaticConfig{{Name: "hw", Cases: []string{"01", "02"}}},
+		Clock:      clock,
+		ReceiptKey: key,
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	sub := &pb.UserSubmission{
+		User:     "ipc21s001",
+		Homework: "hw",
+		Results:  []*pb.Result{{Case: "01", Passed: true, Time: 2}},
+		CodeHash: "0123",
+	}
+	rep, err := h.Client.Submit(context.Background(), sub)
+	require.NoError(t, err)
+	assert.Equal(t, pb.SubmissionRecord_CREATED, rep.Record.Outcome)
+	assert.Nil(t, rep.Record.OldScore)
+	assert.Equal(t, int32(1), rep.Record.NewScore.NumPassed)
+	assert.Equal(t, clock.Now().UnixNano(), rep.Record.TimeUnixNano)
+	assert.Equal(t, "0123", rep.Record.CodeHash)
+	firstID := rep.Record.Id
+
+	clock.Advance(time.Minute)
+	sub.Results = append(sub.Results, &pb.Result{Case: "02", Passed: true, Time: 1})
+	rep, err = h.Client.Submit(context.Background(), sub)
+	require.NoError(t, err)
+	assert.Equal(t, pb.SubmissionRecord_UPDATED, rep.Record.Outcome)
+	assert.Equal(t, int32(1), rep.Record.OldScore.NumPassed)
+	assert.Equal(t, int32(2), rep.Record.NewScore.NumPassed)
+	assert.NotEqual(t, firstID, rep.Record.Id)
+
+	record, err := server.VerifyReceipt(key.Public().(ed25519.PublicKey), rep.Receipt)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(rep.Record, record))
+
+	clock.Advance(time.Minute)
+	rep, err = h.Client.Submit(context.Background(), sub)
+	require.NoError(t, err)
+	assert.Equal(t, pb.SubmissionRecord_NOT_UPDATED, rep.Record.Outcome)
+}
@@ -0,0 +1,214 @@
+// Package servertest runs a scoreboard server in process for tests
+package servertest
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/NTHU-lsalab/sb/server"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// MemoryStorage keeps the submissions in memory
+type MemoryStorage struct {
+	mu          sync.Mutex
+	submissions map[string]map[string]*pb.StoredSubmission
+}
+
+// NewMemoryStorage returns an empty MemoryStorage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{submissions: make(map[string]map[string]*pb.StoredSubmission)}
+}
+
+// Load implements server.Storage
+func (s *MemoryStorage) Load(homework string) ([]*pb.StoredSubmission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var submissions []*pb.StoredSubmission
+	for _, submission := range s.submissions[homework] {
+		submissions = append(submissions, proto.Clone(submission).(*pb.StoredSubmission))
+	}
+	return submissions, nil
+}
+
+// Store implements server.Storage
+func (s *MemoryStorage) Store(homework string, submission *pb.StoredSubmission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.submissions[homework] == nil {
+		s.submissions[homework] = make(map[string]*pb.StoredSubmission)
+	}
+	s.submissions[homework][submission.User] = proto.Clone(submission).(*pb.StoredSubmission)
+	return nil
+}
+
+// Delete implements server.Storage
+func (s *MemoryStorage) Delete(homework, user string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.submissions[homework], user)
+	return nil
+}
+
+// Get returns the stored submission of the user
+func (s *MemoryStorage) Get(homework, user string) *pb.StoredSubmission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.submissions[homework][user]
+}
+
+// FakeClock is a clock that only moves when told to
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a clock stopped at now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now implements server.Clock
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// RecordingRenderer counts the renders of each board
+type RecordingRenderer struct {
+	mu      sync.Mutex
+	renders map[string]int
+}
+
+// Render implements server.Renderer
+func (r *RecordingRenderer) Render(b *server.Board) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.renders == nil {
+		r.renders = make(map[string]int)
+	}
+	r.renders[b.Homework.Name]++
+	return nil
+}
+
+// Renders returns the number of times the board of the homework was rendered
+func (r *RecordingRenderer) Renders(homework string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.renders[homework]
+}
+
+// Harness is a scoreboard server served over an in-memory connection, or a
+// unix socket
+type Harness struct {
+	Server *server.Server
+	Client pb.ScoreboardClient
+	Admin  pb.AdminClient
+	Conn   *grpc.ClientConn
+
+	grpcServer *grpc.Server
+	dir        string // of the unix socket, removed on Close
+}
+
+// New starts a server with the options. Unset config, storage, renderer, clock
+// and logger default to no homeworks, a MemoryStorage, a RecordingRenderer, a
+// FakeClock and a discarding logger.
+func New(opts server.Options) (*Harness, error) {
+	lis := bufconn.Listen(1 << 20)
+	return start(opts, lis, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+}
+
+// NewUnix is New serving on a unix socket with server.PeerCredentials, so that
+// the client is known to the server by its uid
+func NewUnix(opts server.Options) (*Harness, error) {
+	dir, err := ioutil.TempDir("", "servertest")
+	if err != nil {
+		return nil, err
+	}
+	addr := filepath.Join(dir, "sb.sock")
+	lis, err := net.Listen("unix", addr)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	h, err := start(opts, lis, grpc.WithContextDialer(func(ctx context.Context, target string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", addr)
+	}), grpc.Creds(server.PeerCredentials()))
+	if err != nil {
+		lis.Close()
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	h.dir = dir
+	return h, nil
+}
+
+func start(opts server.Options, lis net.Listener, dialer grpc.DialOption, serverOpts ...grpc.ServerOption) (*Harness, error) {
+	if opts.Config == nil {
+		opts.Config = server.StaticConfig{}
+	}
+	if opts.Storage == nil {
+		opts.Storage = NewMemoryStorage()
+	}
+	if opts.Renderer == nil {
+		opts.Renderer = &RecordingRenderer{}
+	}
+	if opts.Clock == nil {
+		opts.Clock = NewFakeClock(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC))
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.New(ioutil.Discard, "", 0)
+	}
+	s, err := server.New(opts)
+	if err != nil {
+		return nil, err
+	}
+	gs := grpc.NewServer(append(serverOpts,
+		grpc.UnaryInterceptor(s.UnaryInterceptor()),
+		grpc.StreamInterceptor(s.StreamInterceptor()))...)
+	pb.RegisterScoreboardServer(gs, s)
+	pb.RegisterAdminServer(gs, s.Admin())
+	go gs.Serve(lis)
+	conn, err := grpc.Dial("bufnet", dialer, grpc.WithInsecure())
+	if err != nil {
+		gs.Stop()
+		return nil, err
+	}
+	return &Harness{
+		Server:     s,
+		Client:     pb.NewScoreboardClient(conn),
+		Admin:      pb.NewAdminClient(conn),
+		Conn:       conn,
+		grpcServer: gs,
+	}, nil
+}
+
+// Close stops the server
+func (h *Harness) Close() {
+	h.Conn.Close()
+	h.grpcServer.Stop()
+	if h.dir != "" {
+		os.RemoveAll(h.dir)
+	}
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NTHU-lsalab/sb/pb"
+)
+
+// Storage persists the best submission of each user of each homework
+type Storage interface {
+	// Load returns the stored submissions of the homework
+	Load(homework string) ([]*pb.StoredSubmission, error)
+	// Store replaces the stored submission of the user
+	Store(homework string, submission *pb.StoredSubmission) error
+	// Delete removes the stored submission of the user, if any
+	Delete(homework, user string) error
+}
+
+// QuarantineDir is the directory under DirStorage.Dir where stored submissions
+// that cannot be loaded are moved to
+const QuarantineDir = ".quarantine"
+
+// DirStorage stores each submission as JSON in Dir/<homework>/<user>.json.
+//
+// Load moves the files that cannot be loaded to Dir/.quarantine/<homework>,
+// and appends the reason to the REPORT file there. A <user>.json- file left
+// over by an interrupted Store is the newer submission if it is complete, and
+// replaces <user>.json; otherwise it is removed.
+type DirStorage struct {
+	Dir    string
+	Logger *log.Logger // reports the stored submissions that cannot be loaded
+}
+
+func (s *DirStorage) logf(format string, args ...interface{}) {
+	if s.Logger == nil {
+		log.Printf(format, args...)
+		return
+	}
+	s.Logger.Printf(format, args...)
+}
+
+// readSubmission reads the stored submission of the user from the file
+func readSubmission(filename, user string) (*pb.StoredSubmission, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	submission := &pb.StoredSubmission{}
+	err = json.Unmarshal(data, submission)
+	if err != nil {
+		return nil, err
+	}
+	if submission.User == "" {
+		return nil, errors.New("empty user")
+	}
+	if submission.User != user {
+		return nil, fmt.Errorf("user %q does not match the file name", submission.User)
+	}
+	return submission, nil
+}
+
+// quarantine moves the file out of the way of Load
+func (s *DirStorage) quarantine(homework, filename string, reason error) error {
+	dir := filepath.Join(s.Dir, QuarantineDir, homework)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	dest := filepath.Join(dir, filepath.Base(filename))
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(dest); os.IsNotExist(err) {
+			break
+		}
+		dest = filepath.Join(dir, fmt.Sprintf("%s.%d", filepath.Base(filename), i))
+	}
+	err = os.Rename(filename, dest)
+	if err != nil {
+		return err
+	}
+	report, err := os.OpenFile(filepath.Join(dir, "REPORT"), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer report.Close()
+	_, err = fmt.Fprintf(report, "%s %s -> %s: %v\n",
+		time.Now().Format(time.RFC3339), filename, filepath.Base(dest), reason)
+	return err
+}
+
+// recoverLeftovers handles the <user>.json- files of interrupted writes,
+// returning the number of files recovered and removed
+func (s *DirStorage) recoverLeftovers(hwDir string) (recovered, cleaned int) {
+	glob, err := filepath.Glob(filepath.Join(hwDir, "*.json-"))
+	if err != nil {
+		panic(err) // malformed glob
+	}
+	for _, leftover := range glob {
+		filename := strings.TrimSuffix(leftover, "-")
+		user := strings.TrimSuffix(filepath.Base(filename), ".json")
+		if _, err := readSubmission(leftover, user); err != nil {
+			s.logf("Removing incomplete write %s: %v", leftover, err)
+			if err := os.Remove(leftover); err != nil {
+				s.logf("Failed to remove %s: %v", leftover, err)
+				continue
+			}
+			cleaned++
+			continue
+		}
+		s.logf("Recovering interrupted write %s", leftover)
+		if err := os.Rename(leftover, filename); err != nil {
+			s.logf("Failed to recover %s: %v", leftover, err)
+			continue
+		}
+		recovered++
+	}
+	return
+}
+
+// Load implements Storage
+func (s *DirStorage) Load(homework string) ([]*pb.StoredSubmission, error) {
+	hwDir := filepath.Join(s.Dir, homework)
+	err := os.MkdirAll(hwDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	recovered, cleaned := s.recoverLeftovers(hwDir)
+	glob, err := filepath.Glob(filepath.Join(hwDir, "*.json"))
+	if err != nil {
+		panic(err) // malformed glob
+	}
+	var submissions []*pb.StoredSubmission
+	quarantined := 0
+	for _, filename := range glob {
+		user := strings.TrimSuffix(filepath.Base(filename), ".json")
+		submission, err := readSubmission(filename, user)
+		if err != nil {
+			s.logf("Quarantining stored submission %s: %v", filename, err)
+			if qerr := s.quarantine(homework, filename, err); qerr != nil {
+				s.logf("Failed to quarantine %s: %v", filename, qerr)
+			}
+			quarantined++
+			continue
+		}
+		submissions = append(submissions, submission)
+	}
+	s.logf("Loaded %s: %d submissions, %d quarantined, %d recovered, %d incomplete writes removed",
+		homework, len(submissions), quarantined, recovered, cleaned)
+	return submissions, nil
+}
+
+// Store implements Storage
+func (s *DirStorage) Store(homework string, submission *pb.StoredSubmission) error {
+	b, err := json.Marshal(submission)
+	if err != nil {
+		return err
+	}
+	outputFile := filepath.Join(s.Dir, homework, submission.User) + ".json"
+	err = ioutil.WriteFile(outputFile+"-", b, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(outputFile+"-", outputFile)
+}
+
+// Delete implements Storage
+func (s *DirStorage) Delete(homework, user string) error {
+	err := os.Remove(filepath.Join(s.Dir, homework, user) + ".json")
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirStorageLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	hwDir := filepath.Join(dir, "hw")
+	require.NoError(t, os.Mkdir(hwDir, 0755))
+	for name, content := range map[string]string{
+		"good.json":      `{"user":"good","results":[{"case":"01","passed":true,"time":1}]}`,
+		"truncated.json": `{"user":"trunc`,
+		"nouser.json":    `{"results":[]}`,
+		"other.json":     `{"user":"someone"}`,
+		// interrupted after writing the better submission
+		"newer.json":  `{"user":"newer","results":[{"case":"01","passed":false}]}`,
+		"newer.json-": `{"user":"newer","results":[{"case":"01","passed":true,"time":2}]}`,
+		// interrupted while writing
+		"partial.json":  `{"user":"partial"}`,
+		"partial.json-": `{"user":"par`,
+		"only.json-":    `{"user":"only"}`,
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(hwDir, name), []byte(content), 0644))
+	}
+
+	var logs bytes.Buffer
+	s := &DirStorage{Dir: dir, Logger: log.New(&logs, "", 0)}
+	submissions, err := s.Load("hw")
+	require.NoError(t, err)
+
+	users := make(map[string]*pb.StoredSubmission)
+	for _, sub := range submissions {
+		users[sub.User] = sub
+	}
+	assert.Len(t, users, 4)
+	assert.Contains(t, users, "good")
+	assert.Contains(t, users, "partial")
+	assert.Contains(t, users, "only")
+	require.Contains(t, users, "newer")
+	assert.True(t, users["newer"].Results[0].Passed)
+	assert.Contains(t, logs.String(), "Loaded hw: 4 submissions, 3 quarantined, 2 recovered, 1 incomplete writes removed")
+
+	files, err := filepath.Glob(filepath.Join(hwDir, "*"))
+	require.NoError(t, err)
+	for i := range files {
+		files[i] = filepath.Base(files[i])
+	}
+	sort.Strings(files)
+	assert.Equal(t, []string{"good.json", "newer.json", "only.json", "partial.json"}, files)
+
+	qDir := filepath.Join(dir, QuarantineDir, "hw")
+	for _, name := range []string{"truncated.json", "nouser.json", "other.json"} {
+		assert.FileExists(t, filepath.Join(qDir, name))
+	}
+	report, err := ioutil.ReadFile(filepath.Join(qDir, "REPORT"))
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "other.json -> other.json: user \"someone\" does not match the file name")
+	assert.Contains(t, string(report), "nouser.json -> nouser.json: empty user")
+
+	// a second corrupt file of the same name does not overwrite the first
+	require.NoError(t, ioutil.WriteFile(filepath.Join(hwDir, "other.json"), []byte("{"), 0644))
+	_, err = s.Load("hw")
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(qDir, "other.json.1"))
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxSubmissionSize is the default limit of the encoded size of a submission
+const DefaultMaxSubmissionSize = 1 << 20
+
+// failedVerdicts are the verdicts of failed results, given by the judge or by
+// the runners
+var failedVerdicts = []string{
+	"internal error",
+	"memory limit exceeded",
+	"wrong answer",
+	"runtime error",
+	"time limit exceeded",
+}
+
+// violations collects the problems of a submission
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, format string, args ...interface{}) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// err returns an InvalidArgument status with the violations as details, or nil
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	descriptions := make([]string, len(v))
+	for i, fv := range v {
+		descriptions[i] = fv.Field + ": " + fv.Description
+	}
+	st := status.New(codes.InvalidArgument, "invalid submission: "+strings.Join(descriptions, "; "))
+	st, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v})
+	if err != nil {
+		panic(err) // BadRequest can always be marshaled
+	}
+	return st.Err()
+}
+
+func finite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+// validUser tells whether the user name can name the file of a stored submission
+func validUser(user string) bool {
+	return user != "" && !strings.ContainsAny(user, "/\\\x00") && !strings.HasPrefix(user, ".")
+}
+
+// validateSubmission checks that the submission can be scored on the board of
+// the homework
+func validateSubmission(hw *pb.Homework, sub *pb.UserSubmission, maxSize int) error {
+	var v violations
+	if size := proto.Size(sub); size > maxSize {
+		v.add("submission", "size %d exceeds the limit of %d bytes", size, maxSize)
+		return v.err()
+	}
+	if sub.User == "" {
+		v.add("user", "empty user")
+	} else if !validUser(sub.User) {
+		v.add("user", "invalid user name %q", sub.User)
+	}
+	caseMap := caseMapFromHomework(hw)
+	seen := make(map[string]bool)
+	for i, r := range sub.Results {
+		field := fmt.Sprintf("results[%d]", i)
+		if _, ok := caseMap[r.Case]; !ok {
+			v.add(field+".case", "unknown case %q", r.Case)
+		} else if seen[r.Case] {
+			v.add(field+".case", "duplicate case %q", r.Case)
+		}
+		seen[r.Case] = true
+		if !finite(r.Time) || r.Time < 0 {
+			v.add(field+".time", "time %v is not a finite non-negative number", r.Time)
+		}
+		if !finite(r.CpuTime) || r.CpuTime < 0 {
+			v.add(field+".cpu_time", "cpu time %v is not a finite non-negative number", r.CpuTime)
+		}
+		if r.Memory < 0 {
+			v.add(field+".memory", "negative memory %d", r.Memory)
+		}
+		for name, value := range r.Metrics {
+			if !finite(value) {
+				v.add(field+".metrics", "metric %q is %v", name, value)
+			}
+		}
+		verdict := strings.ToLower(r.Verdict)
+		if !r.Passed && verdict == "accepted" {
+			v.add(field+".verdict", "failed result with verdict %q", r.Verdict)
+		}
+		for _, failed := range failedVerdicts {
+			if r.Passed && verdict == failed {
+				v.add(field+".verdict", "passed result with verdict %q", r.Verdict)
+			}
+		}
+	}
+	return v.err()
+}
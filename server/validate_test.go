@@ -0,0 +1,74 @@
+package server
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateSubmission(t *testing.T) {
+	hw := &pb.Homework{Name: "hw", Cases: []string{"01", "02"}}
+	valid := func() *pb.UserSubmission {
+		return &pb.UserSubmission{User: "ipc21s001", Homework: "hw", Results: []*pb.Result{
+			{Case: "01", Passed: true, Time: 1, Verdict: "Accepted"},
+			{Case: "02", Passed: false, Time: 0, Verdict: "internal error"},
+		}}
+	}
+	assert.NoError(t, validateSubmission(hw, valid(), DefaultMaxSubmissionSize))
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*pb.UserSubmission)
+		field  string
+	}{
+		{"empty user", func(s *pb.UserSubmission) { s.User = "" }, "user"},
+		{"path as user", func(s *pb.UserSubmission) { s.User = "../ipc21s001" }, "user"},
+		{"unknown case", func(s *pb.UserSubmission) { s.Results[1].Case = "03" }, "results[1].case"},
+		{"duplicate case", func(s *pb.UserSubmission) { s.Results[1].Case = "01" }, "results[1].case"},
+		{"negative time", func(s *pb.UserSubmission) { s.Results[0].Time = -1 }, "results[0].time"},
+		{"NaN time", func(s *pb.UserSubmission) { s.Results[0].Time = math.NaN() }, "results[0].time"},
+		{"infinite cpu time", func(s *pb.UserSubmission) { s.Results[0].CpuTime = math.Inf(1) }, "results[0].cpu_time"},
+		{"negative memory", func(s *pb.UserSubmission) { s.Results[0].Memory = -1 }, "results[0].memory"},
+		{"NaN metric", func(s *pb.UserSubmission) { s.Results[0].Metrics = map[string]float64{"x": math.NaN()} }, "results[0].metrics"},
+		{"failed but accepted", func(s *pb.UserSubmission) { s.Results[0].Passed = false }, "results[0].verdict"},
+		{"passed with internal error", func(s *pb.UserSubmission) { s.Results[1].Passed = true }, "results[1].verdict"},
+		{"passed with memory limit exceeded", func(s *pb.UserSubmission) {
+			s.Results[1].Passed, s.Results[1].Verdict = true, "memory limit exceeded"
+		}, "results[1].verdict"},
+		{"passed with wrong answer", func(s *pb.UserSubmission) {
+			s.Results[1].Passed, s.Results[1].Verdict = true, "Wrong Answer"
+		}, "results[1].verdict"},
+		{"passed with runtime error", func(s *pb.UserSubmission) {
+			s.Results[1].Passed, s.Results[1].Verdict = true, "runtime error"
+		}, "results[1].verdict"},
+		{"passed with time limit exceeded", func(s *pb.UserSubmission) {
+			s.Results[1].Passed, s.Results[1].Verdict = true, "Time Limit Exceeded"
+		}, "results[1].verdict"},
+		{"too large", func(s *pb.UserSubmission) { s.Code = make([]byte, DefaultMaxSubmissionSize) }, "submission"},
+	} {
+		sub := valid()
+		tc.modify(sub)
+		err := validateSubmission(hw, sub, DefaultMaxSubmissionSize)
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code(), tc.name)
+		require.Len(t, st.Details(), 1, tc.name)
+		violations := st.Details()[0].(*errdetails.BadRequest).FieldViolations
+		require.Len(t, violations, 1, tc.name)
+		assert.Equal(t, tc.field, violations[0].Field, tc.name)
+		assert.True(t, strings.HasPrefix(st.Message(), "invalid submission: "+tc.field+": "), st.Message())
+	}
+}
+
+func TestValidateSubmissionReportsAll(t *testing.T) {
+	hw := &pb.Homework{Name: "hw", Cases: []string{"01"}}
+	err := validateSubmission(hw, &pb.UserSubmission{Results: []*pb.Result{{Case: "01", Time: -1}}}, DefaultMaxSubmissionSize)
+	assert.EqualError(t, err, "rpc error: code = InvalidArgument desc = invalid submission: "+
+		"user: empty user; results[0].time: time -1 is not a finite non-negative number")
+}
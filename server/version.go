@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/NTHU-lsalab/sb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// clientVersion returns the protocol and build version sent by the client.
+// Clients predating the handshake send neither and have protocol version 0.
+func clientVersion(ctx context.Context) (protocol int, version string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(sb.ProtocolVersionKey); len(v) > 0 {
+		protocol, _ = strconv.Atoi(v[0])
+	}
+	if v := md.Get(sb.ClientVersionKey); len(v) > 0 {
+		version = v[0]
+	}
+	return
+}
+
+// checkVersion rejects clients older than the minimum protocol version
+func (s *Server) checkVersion(ctx context.Context, method string) error {
+	protocol, version := clientVersion(ctx)
+	if protocol >= s.minProtocol {
+		return nil
+	}
+	if version == "" {
+		version = "unknown"
+	}
+	s.logger.Printf("Refused %s from a client of protocol version %d (build %s)", method, protocol, version)
+	return status.Errorf(codes.FailedPrecondition,
+		"the judge is too old (protocol version %d, build %s): the scoreboard requires protocol version %d or newer, please upgrade xjudge",
+		protocol, version, s.minProtocol)
+}
+
+func (s *Server) sendVersion(ctx context.Context) {
+	grpc.SetHeader(ctx, metadata.Pairs(
+		sb.ServerVersionKey, sb.Version,
+		sb.ProtocolVersionKey, strconv.Itoa(sb.ProtocolVersion)))
+}
+
+// UnaryInterceptor returns the interceptor of the version handshake, which
+// tells clients the version of the server and refuses clients that are too old
+func (s *Server) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		s.sendVersion(ctx)
+		if err := s.checkVersion(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is the UnaryInterceptor of streaming calls
+func (s *Server) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		s.sendVersion(ss.Context())
+		if err := s.checkVersion(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
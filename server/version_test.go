@@ -0,0 +1,59 @@
+package server_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb"
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/NTHU-lsalab/sb/server"
+	"github.com/NTHU-lsalab/sb/server/servertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestVersionHandshake(t *testing.T) {
+	h, err := servertest.New(server.Options{
+		Config:             server.StaticConfig{{Name: "hw", Cases: []string{"01"}}},
+		MinProtocolVersion: sb.ProtocolVersion,
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	// a client predating the handshake
+	_, err = h.Client.QueryHomework(context.Background(), &pb.QueryHomeworkRequest{Name: "hw"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, err.Error(), "protocol version 0, build unknown")
+	assert.Contains(t, err.Error(), "please upgrade xjudge")
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		sb.ProtocolVersionKey, strconv.Itoa(sb.ProtocolVersion),
+		sb.ClientVersionKey, "test")
+	var header metadata.MD
+	hw, err := h.Client.QueryHomework(ctx, &pb.QueryHomeworkRequest{Name: "hw"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, sb.Version, hw.ServerVersion)
+	assert.Equal(t, []string{sb.Version}, header.Get(sb.ServerVersionKey))
+	assert.Equal(t, []string{strconv.Itoa(sb.ProtocolVersion)}, header.Get(sb.ProtocolVersionKey))
+
+	// the stored homework is not modified
+	board, ok := h.Server.Board("hw")
+	require.True(t, ok)
+	assert.Equal(t, "", board.Homework.ServerVersion)
+}
+
+func TestVersionHandshakeAcceptsAll(t *testing.T) {
+	h, err := servertest.New(server.Options{
+		Config: server.StaticConfig{{Name: "hw", Cases: []string{"01"}}},
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	_, err = h.Client.QueryHomework(context.Background(), &pb.QueryHomeworkRequest{Name: "hw"})
+	assert.NoError(t, err)
+}
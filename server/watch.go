@@ -0,0 +1,149 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/NTHU-lsalab/sb/pb"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchHistory is the number of events kept for resuming watchers
+const watchHistory = 256
+
+// boardWatch keeps the recent events of a board for its watchers
+type boardWatch struct {
+	mu       sync.Mutex
+	seq      uint64           // sequence number of the last event
+	events   []*pb.BoardEvent // the last events, up to watchHistory
+	last     *pb.Board        // the board as of seq
+	watchers map[chan struct{}]struct{}
+}
+
+// publish diffs the board against the last snapshot and wakes up the watchers
+// if any row changed or was removed. It is called with submissionLock held.
+func (b *Board) publish() {
+	board := b.snapshot()
+	w := &b.watch
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	old := make(map[string]*pb.BoardRow)
+	if w.last != nil {
+		for _, row := range w.last.Rows {
+			old[row.User] = row
+		}
+	}
+	update := &pb.BoardUpdate{}
+	for _, row := range board.Rows {
+		if !proto.Equal(row, old[row.User]) {
+			update.Rows = append(update.Rows, row)
+		}
+		delete(old, row.User)
+	}
+	if w.last != nil {
+		// in the order of the last snapshot
+		for _, row := range w.last.Rows {
+			if _, ok := old[row.User]; ok {
+				update.RemovedUsers = append(update.RemovedUsers, row.User)
+			}
+		}
+	}
+	w.last = board
+	if len(update.Rows) == 0 && len(update.RemovedUsers) == 0 {
+		return
+	}
+	w.seq++
+	w.events = append(w.events, &pb.BoardEvent{
+		ResumeToken: b.server.resumeToken(w.seq),
+		Event:       &pb.BoardEvent_Update{Update: update},
+	})
+	if len(w.events) > watchHistory {
+		w.events = w.events[len(w.events)-watchHistory:]
+	}
+	for ch := range w.watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *Server) resumeToken(seq uint64) string {
+	return fmt.Sprintf("%x.%d", s.epoch, seq)
+}
+
+// parseResumeToken returns the sequence number of the token, or false if the
+// token was not issued by this run of the server
+func (s *Server) parseResumeToken(token string) (uint64, bool) {
+	var epoch int64
+	var seq uint64
+	if _, err := fmt.Sscanf(token, "%x.%d", &epoch, &seq); err != nil || epoch != s.epoch {
+		return 0, false
+	}
+	return seq, true
+}
+
+// eventsSince returns the events after seq, or a snapshot if some of them are
+// no longer kept
+func (w *boardWatch) eventsSince(s *Server, seq uint64) ([]*pb.BoardEvent, uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if seq > w.seq || w.seq-seq > uint64(len(w.events)) {
+		return []*pb.BoardEvent{{
+			ResumeToken: s.resumeToken(w.seq),
+			Event:       &pb.BoardEvent_Snapshot{Snapshot: w.last},
+		}}, w.seq
+	}
+	return w.events[len(w.events)-int(w.seq-seq):], w.seq
+}
+
+func (w *boardWatch) subscribe() chan struct{} {
+	ch := make(chan struct{}, 1)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.watchers == nil {
+		w.watchers = make(map[chan struct{}]struct{})
+	}
+	w.watchers[ch] = struct{}{}
+	return ch
+}
+
+func (w *boardWatch) unsubscribe(ch chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.watchers, ch)
+}
+
+// WatchBoard streams the board. Without a resume token, or with a token that
+// is too old or from a previous run of the server, it starts with a snapshot.
+func (s *Server) WatchBoard(req *pb.WatchBoardRequest, stream pb.Scoreboard_WatchBoardServer) error {
+	b, ok := s.Board(req.Homework)
+	if !ok {
+		return status.Errorf(codes.NotFound, "no such homework %q", req.Homework)
+	}
+	ch := b.watch.subscribe()
+	defer b.watch.unsubscribe(ch)
+
+	seq, ok := s.parseResumeToken(req.ResumeToken)
+	if !ok {
+		// more than the number of events kept, which forces a snapshot
+		seq = ^uint64(0)
+	}
+	for {
+		var events []*pb.BoardEvent
+		events, seq = b.watch.eventsSince(s, seq)
+		for _, event := range events {
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ch:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
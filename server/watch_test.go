@@ -0,0 +1,148 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NTHU-lsalab/sb/pb"
+	"github.com/NTHU-lsalab/sb/server"
+	"github.com/NTHU-lsalab/sb/server/servertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func submit(t *testing.T, h *servertest.Harness, user string, time float64) {
+	_, err := h.Client.Submit(context.Background(), &pb.UserSubmission{
+		User:     user,
+		Homework: "hw",
+		Results:  []*pb.Result{{Case: "01", Passed: true, Time: time}},
+	})
+	require.NoError(t, err)
+}
+
+func watch(t *testing.T, h *servertest.Harness, token string) (pb.Scoreboard_WatchBoardClient, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := h.Client.WatchBoard(ctx, &pb.WatchBoardRequest{Homework: "hw", ResumeToken: token})
+	require.NoError(t, err)
+	return stream, cancel
+}
+
+func recv(t *testing.T, stream pb.Scoreboard_WatchBoardClient) *pb.BoardEvent {
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	return event
+}
+
+func users(rows []*pb.BoardRow) []string {
+	var users []string
+	for _, row := range rows {
+		users = append(users, row.User)
+	}
+	return users
+}
+
+func TestWatchBoard(t *testing.T) {
+	h, err := servertest.New(server.Options{
+		Config: server.StaticConfig{{Name: "hw", Cases: []string{"01"}}},
+	})
+	require.NoError(t, err)
+	defer h.Close()
+	submit(t, h, "ipc21s001", 2)
+
+	stream, cancel := watch(t, h, "")
+	defer cancel()
+	event := recv(t, stream)
+	require.NotNil(t, event.GetSnapshot())
+	assert.Equal(t, []string{"ipc21s001"}, users(event.GetSnapshot().Rows))
+
+	// the new row ranks first, moving the other one down
+	submit(t, h, "ipc21s002", 1)
+	event = recv(t, stream)
+	require.NotNil(t, event.GetUpdate())
+	rows := event.GetUpdate().Rows
+	assert.Equal(t, []string{"ipc21s002", "ipc21s001"}, users(rows))
+	assert.Equal(t, []int32{1, 2}, []int32{rows[0].Rank, rows[1].Rank})
+	token := event.ResumeToken
+
+	// only the changed row, as the ranks stay the same
+	submit(t, h, "ipc21s001", 1.5)
+	event = recv(t, stream)
+	assert.Equal(t, []string{"ipc21s001"}, users(event.GetUpdate().Rows))
+	cancel()
+
+	submit(t, h, "ipc21s003", 3)
+
+	// resuming replays the missed events
+	stream, cancel = watch(t, h, token)
+	defer cancel()
+	event = recv(t, stream)
+	assert.Equal(t, []string{"ipc21s001"}, users(event.GetUpdate().Rows))
+	event = recv(t, stream)
+	assert.Equal(t, []string{"ipc21s003"}, users(event.GetUpdate().Rows))
+
+	// an unknown token starts over with a snapshot
+	stream, cancel = watch(t, h, "bogus")
+	defer cancel()
+	event = recv(t, stream)
+	assert.Equal(t, []string{"ipc21s002", "ipc21s001", "ipc21s003"}, users(event.GetSnapshot().Rows))
+}
+
+func TestWatchBoardDeletedRow(t *testing.T) {
+	h, err := servertest.NewUnix(server.Options{
+		Config: server.StaticConfig{{Name: "hw", Cases: []string{"01"}}},
+	})
+	require.NoError(t, err)
+	defer h.Close()
+	submit(t, h, "ipc21s001", 1)
+	submit(t, h, "ipc21s002", 2)
+
+	stream, cancel := watch(t, h, "")
+	defer cancel()
+	event := recv(t, stream)
+	assert.Equal(t, []string{"ipc21s001", "ipc21s002"}, users(event.GetSnapshot().Rows))
+
+	// the deleted row is removed, moving the other one up
+	_, err = h.Admin.DeleteEntry(context.Background(), &pb.EntryRequest{Homework: "hw", User: "ipc21s001"})
+	require.NoError(t, err)
+	event = recv(t, stream)
+	require.NotNil(t, event.GetUpdate())
+	assert.Equal(t, []string{"ipc21s001"}, event.GetUpdate().RemovedUsers)
+	rows := event.GetUpdate().Rows
+	assert.Equal(t, []string{"ipc21s002"}, users(rows))
+	assert.EqualValues(t, 1, rows[0].Rank)
+}
+
+func TestWatchBoardResumeTooOld(t *testing.T) {
+	h, err := servertest.New(server.Options{
+		Config: server.StaticConfig{{Name: "hw", Cases: []string{"01"}}},
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	stream, cancel := watch(t, h, "")
+	event := recv(t, stream)
+	cancel()
+	token := event.ResumeToken
+	for i := 0; i < 300; i++ {
+		submit(t, h, "ipc21s001", float64(1000-i))
+	}
+
+	stream, cancel = watch(t, h, token)
+	defer cancel()
+	event = recv(t, stream)
+	require.NotNil(t, event.GetSnapshot())
+	assert.Equal(t, 701.0, event.GetSnapshot().Rows[0].TotalTime)
+}
+
+func TestWatchBoardUnknownHomework(t *testing.T) {
+	h, err := servertest.New(server.Options{})
+	require.NoError(t, err)
+	defer h.Close()
+
+	stream, cancel := watch(t, h, "")
+	defer cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}